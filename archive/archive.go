@@ -0,0 +1,99 @@
+package archive
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Archive records which items have been downloaded, one "platform id" line
+// per item in the same layout as yt-dlp's --download-archive. IDs are the
+// canonical ones each service exposes: YouTube video IDs, Spotify track and
+// episode IDs, Instagram shortcodes and Pinterest pin IDs.
+type Archive struct {
+	Path string
+
+	mu      sync.Mutex
+	entries map[string]bool
+}
+
+// Open loads the archive at path, which is created on the first Add.
+func Open(path string) (*Archive, error) {
+	a := &Archive{Path: path, entries: map[string]bool{}}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return a, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		platform, id, ok := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		if ok && id != "" {
+			a.entries[Key(platform, id)] = true
+		}
+	}
+	return a, scanner.Err()
+}
+
+func Key(platform, id string) string {
+	return strings.ToLower(strings.TrimSpace(platform)) + " " + strings.TrimSpace(id)
+}
+
+func (a *Archive) Has(platform, id string) bool {
+	if a == nil || id == "" {
+		return false
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.entries[Key(platform, id)]
+}
+
+// Add records an item and appends it to the archive file right away, so
+// an interrupted batch keeps everything it finished.
+func (a *Archive) Add(platform, id string) error {
+	if id == "" {
+		return errors.New("id cannot be empty")
+	}
+
+	key := Key(platform, id)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.entries[key] {
+		return nil
+	}
+
+	if a.Path != "" {
+		if err := os.MkdirAll(filepath.Dir(a.Path), 0o755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(a.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(f, key); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+
+	a.entries[key] = true
+	return nil
+}
+
+func (a *Archive) Len() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.entries)
+}
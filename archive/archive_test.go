@@ -0,0 +1,91 @@
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	for _, tc := range []struct {
+		platform, id, want string
+	}{
+		{"youtube", "dQw4w9WgXcQ", "youtube dQw4w9WgXcQ"},
+		{" YouTube ", " dQw4w9WgXcQ\n", "youtube dQw4w9WgXcQ"},
+		{"Spotify", "4uLU6hMCjMI75M1A2tKUQC", "spotify 4uLU6hMCjMI75M1A2tKUQC"},
+	} {
+		if got := Key(tc.platform, tc.id); got != tc.want {
+			t.Errorf("Key(%q, %q) = %q, want %q", tc.platform, tc.id, got, tc.want)
+		}
+	}
+}
+
+func TestArchive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "archive.txt")
+
+	a, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Len() != 0 {
+		t.Fatalf("new archive has %d entries", a.Len())
+	}
+
+	for _, item := range [][2]string{
+		{"youtube", "dQw4w9WgXcQ"},
+		{"YouTube", "dQw4w9WgXcQ"},
+		{"instagram", "C1a2B3c4D5e"},
+	} {
+		if err := a.Add(item[0], item[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := a.Add("youtube", ""); err == nil {
+		t.Error("Add with an empty id succeeded")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "youtube dQw4w9WgXcQ\ninstagram C1a2B3c4D5e\n"; string(data) != want {
+		t.Errorf("archive file = %q, want %q", data, want)
+	}
+
+	// Lines yt-dlp or a user wrote are read as well.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("\n  Pinterest 123456  \nbroken\n")
+	f.Close()
+
+	reloaded, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.Len() != 3 {
+		t.Errorf("reloaded archive has %d entries, want 3", reloaded.Len())
+	}
+	for _, tc := range []struct {
+		platform, id string
+		want         bool
+	}{
+		{"youtube", "dQw4w9WgXcQ", true},
+		{"YOUTUBE", "dQw4w9WgXcQ", true},
+		{"youtube", "dqw4w9wgxcq", false},
+		{"instagram", "C1a2B3c4D5e", true},
+		{"pinterest", "123456", true},
+		{"spotify", "dQw4w9WgXcQ", false},
+		{"youtube", "", false},
+	} {
+		if got := reloaded.Has(tc.platform, tc.id); got != tc.want {
+			t.Errorf("Has(%q, %q) = %v, want %v", tc.platform, tc.id, got, tc.want)
+		}
+	}
+
+	var none *Archive
+	if none.Has("youtube", "dQw4w9WgXcQ") {
+		t.Error("nil archive has an entry")
+	}
+}
@@ -0,0 +1,60 @@
+// Package capability describes what a provider can download, so the
+// services only send a link to providers that support its kind of content.
+package capability
+
+import "slices"
+
+// Content kinds, as returned by the services' ContentKind functions.
+const (
+	Track     = "track"
+	Video     = "video"
+	Short     = "short"
+	Live      = "live"
+	Channel   = "channel"
+	Post      = "post"
+	Reel      = "reel"
+	Story     = "story"
+	Highlight = "highlight"
+	Pin       = "pin"
+)
+
+type Capabilities struct {
+	// Kinds lists the content kinds the provider can download.
+	Kinds []string `json:"kinds"`
+	// MaxQuality is the best quality label the provider offers, such as
+	// "1080p" or "320kbps", or empty when it does not say.
+	MaxQuality string `json:"max_quality,omitempty"`
+	// AudioOnly is set for providers that never return video.
+	AudioOnly bool `json:"audio_only,omitempty"`
+	// Carousel is set for providers that return every item of posts with
+	// several photos or videos.
+	Carousel bool `json:"carousel,omitempty"`
+}
+
+// Provider is implemented by providers that declare what they support. It
+// is optional: the services' own Provider interfaces do not require it.
+type Provider interface {
+	Capabilities() Capabilities
+}
+
+// Supports reports whether kind is one of c.Kinds.
+func (c Capabilities) Supports(kind string) bool {
+	return slices.Contains(c.Kinds, kind)
+}
+
+// Filter returns the providers of list that support kind, keeping their
+// order. Providers that do not declare capabilities are kept, as are all
+// providers when kind is empty.
+func Filter[P any](list []P, kind string) []P {
+	if kind == "" {
+		return list
+	}
+	var out []P
+	for _, p := range list {
+		if cp, ok := any(p).(Provider); ok && !cp.Capabilities().Supports(kind) {
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
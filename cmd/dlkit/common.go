@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Beesonn/dlkitgo"
+)
+
+// newKit returns a client that uses the cookies.txt file named by
+// DLKIT_<PLATFORM>_COOKIES for each platform that has one, e.g.
+// DLKIT_YOUTUBE_COOKIES. DLKIT_INSTAGRAM_SESSIONID can stand in for an
+// Instagram cookie file. An Instagram session is checked before use.
+func newKit() (*dlkitgo.Dlkit, error) {
+	kit := dlkitgo.NewClient()
+	for _, platform := range []string{"spotify", "youtube", "instagram", "pinterest"} {
+		env := "DLKIT_" + strings.ToUpper(platform) + "_COOKIES"
+		if path := os.Getenv(env); path != "" {
+			if err := kit.LoadCookies(platform, path); err != nil {
+				return nil, withCode(exitUsage, fmt.Errorf("%s: %w", env, err))
+			}
+		}
+	}
+	if id := os.Getenv("DLKIT_INSTAGRAM_SESSIONID"); id != "" && kit.Instagram.Session == nil {
+		if err := kit.Instagram.SetSessionID(id); err != nil {
+			return nil, withCode(exitUsage, fmt.Errorf("DLKIT_INSTAGRAM_SESSIONID: %w", err))
+		}
+	}
+
+	// An expired session would otherwise only show up later as login walls
+	// on every Instagram request.
+	if kit.Instagram.Session != nil {
+		if _, err := kit.Instagram.ValidateSession(context.Background()); err != nil {
+			return nil, withCode(exitUsage, fmt.Errorf("%w; export fresh cookies or unset the Instagram session variables", err))
+		}
+	}
+	return kit, nil
+}
+
+// requirePlatform detects the service url belongs to.
+func requirePlatform(url string) (string, error) {
+	platform := dlkitgo.DetectPlatform(url)
+	if platform == "" {
+		return "", withCode(exitInvalidURL, fmt.Errorf("unsupported URL: %s", url))
+	}
+	return platform, nil
+}
+
+func oneURL(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", withCode(exitUsage, fmt.Errorf("expected exactly one URL, got %d", len(args)))
+	}
+	return args[0], nil
+}
+
+// useProvider restricts the platform's service to the named provider.
+func useProvider(kit *dlkitgo.Dlkit, platform, name string) (*dlkitgo.Dlkit, error) {
+	kit, err := kit.WithProvider(platform, name)
+	if err != nil {
+		return nil, withCode(exitUsage, err)
+	}
+	return kit, nil
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func printField(label, value string) {
+	if value != "" {
+		fmt.Printf("%-12s %s\n", label+":", value)
+	}
+}
+
+func formatDuration(seconds int) string {
+	if seconds <= 0 {
+		return ""
+	}
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Beesonn/dlkitgo/archive"
+	"github.com/Beesonn/dlkitgo/manager"
+	"github.com/Beesonn/dlkitgo/naming"
+)
+
+func runDownload(args []string) error {
+	fs := flag.NewFlagSet("download", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the finished jobs as JSON")
+	quality := fs.String("quality", "", "YouTube and Pinterest source quality, e.g. 720p, 320kbps, audio")
+	provider := fs.String("provider", "", "use only this provider")
+	template := fs.String("o", "", "output file name template, e.g. \"{artist} - {title}.{ext}\"")
+	dir := fs.String("dir", ".", "directory the output template is relative to")
+	batchFile := fs.String("batch-file", "", "file with one URL per line, - for standard input")
+	archivePath := fs.String("archive", "", "download archive file; archived items are skipped")
+	storePath := fs.String("store", "", "job store file, to resume interrupted batches")
+	workers := fs.Int("workers", manager.DefaultWorkers, "number of parallel downloads")
+	noTag := fs.Bool("no-tag", false, "do not write metadata into downloaded Spotify audio")
+	playlistFormat := fs.String("playlist", "", "write a playlist file for each Spotify album or playlist: m3u, m3u8 or xspf")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	urls := args
+	if *batchFile != "" {
+		batch, err := readBatchFile(*batchFile)
+		if err != nil {
+			return err
+		}
+		urls = append(urls, batch...)
+	}
+	if len(urls) == 0 && *storePath == "" {
+		return withCode(exitUsage, errors.New("no URLs given"))
+	}
+
+	kit, err := newKit()
+	if err != nil {
+		return err
+	}
+	platforms := map[string]bool{}
+	for _, url := range urls {
+		platform, err := requirePlatform(url)
+		if err != nil {
+			return err
+		}
+		platforms[platform] = true
+	}
+	for platform := range platforms {
+		if kit, err = useProvider(kit, platform, *provider); err != nil {
+			return err
+		}
+	}
+
+	m, err := manager.NewManager(kit, *storePath, *dir)
+	if err != nil {
+		return err
+	}
+	m.Workers = *workers
+	m.Quality = *quality
+	m.Tag = !*noTag
+	if *playlistFormat != "" {
+		if !slices.Contains(manager.PlaylistFormats, strings.ToLower(*playlistFormat)) {
+			return withCode(exitUsage, fmt.Errorf("unsupported playlist format %q, use one of: %s", *playlistFormat, strings.Join(manager.PlaylistFormats, ", ")))
+		}
+		m.Playlist = strings.ToLower(*playlistFormat)
+	}
+
+	if *template != "" {
+		for platform := range m.Namers {
+			namer, err := naming.NewNamer(*template, *dir)
+			if err != nil {
+				return withCode(exitUsage, err)
+			}
+			m.Namers[platform] = namer
+		}
+	}
+
+	if *archivePath != "" {
+		if m.Archive, err = archive.Open(*archivePath); err != nil {
+			return err
+		}
+	}
+
+	m.OnUpdate = func(job manager.Job) {
+		switch job.State {
+		case manager.Done:
+			for _, path := range job.Output {
+				fmt.Fprintf(os.Stderr, "done     %s -> %s\n", job.URL, path)
+			}
+		case manager.Skipped:
+			fmt.Fprintf(os.Stderr, "skipped  %s (in archive)\n", job.URL)
+		case manager.Failed:
+			fmt.Fprintf(os.Stderr, "failed   %s: %s\n", job.URL, job.Error)
+			if job.Attempts < m.MaxAttempts {
+				fmt.Fprintf(os.Stderr, "         retrying at %s\n", job.NextAttempt.Format(time.TimeOnly))
+			}
+		}
+	}
+
+	if _, err := m.Add(urls...); err != nil {
+		return withCode(exitInvalidURL, err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := m.Run(ctx); err != nil {
+		return err
+	}
+
+	jobs := m.Store.Jobs()
+	if *asJSON {
+		if err := printJSON(jobs); err != nil {
+			return err
+		}
+	}
+
+	var failed []manager.Job
+	for _, job := range jobs {
+		if job.State == manager.Failed {
+			failed = append(failed, job)
+		}
+	}
+	if len(failed) > 0 {
+		err := fmt.Errorf("%d of %d downloads failed, first: %s", len(failed), len(jobs), failed[0].Error)
+		return withCode(kindCode(failed[0].ErrorKind), err)
+	}
+	return nil
+}
+
+// readBatchFile reads one URL per line, ignoring blank lines and lines
+// starting with # or ;.
+func readBatchFile(path string) ([]string, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var urls []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	return urls, scanner.Err()
+}
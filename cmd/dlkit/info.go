@@ -0,0 +1,117 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Beesonn/dlkitgo"
+	"github.com/Beesonn/dlkitgo/spotify"
+)
+
+func runInfo(args []string) error {
+	fs := flag.NewFlagSet("info", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the metadata as JSON")
+	typeHint := fs.String("type", "", "Spotify type of a bare ID: track, album, playlist, artist, show or episode")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	url, err := oneURL(args)
+	if err != nil {
+		return err
+	}
+
+	platform := dlkitgo.DetectPlatform(url)
+	if platform == "" && spotify.SpotifyIDRegex.MatchString(url) {
+		platform = "spotify"
+	}
+	if platform == "" {
+		return withCode(exitInvalidURL, fmt.Errorf("unsupported URL: %s", url))
+	}
+
+	kit, err := newKit()
+	if err != nil {
+		return err
+	}
+
+	switch platform {
+	case "spotify":
+		info, err := kit.Spotify.GetInfo(url, *typeHint)
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return printJSON(info)
+		}
+		printField("Type", info.Type)
+		printField("Name", info.Name)
+		printField("Artist", info.Artist)
+		printField("Released", info.ReleaseDate)
+		printField("URL", info.URL)
+		printField("Image", info.Image)
+		for i, track := range info.Tracks {
+			fmt.Printf("%3d. %s - %s (%s)\n", i+1, track.Artist, track.Name, formatDuration(track.Duration))
+		}
+		for i, ep := range info.Episodes {
+			fmt.Printf("%3d. %s (%s)\n", i+1, ep.Name, formatDuration(ep.Duration))
+		}
+
+	case "youtube":
+		info, err := kit.Youtube.GetInfo(url)
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return printJSON(info)
+		}
+		printField("Type", info.Type)
+		printField("ID", info.ID)
+		printField("Title", info.Name)
+		printField("URL", info.URL)
+		printField("Thumbnail", info.Image)
+		for _, video := range info.Videos {
+			printField("Duration", formatDuration(video.Duration))
+		}
+
+	case "instagram":
+		info, err := kit.Instagram.GetInfo(url)
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return printJSON(info)
+		}
+		printField("Shortcode", info.Shortcode)
+		printField("Username", info.Username)
+		if !info.TakenAt.IsZero() {
+			printField("Date", info.TakenAt.Format("2006-01-02 15:04"))
+		}
+		printField("Likes", strconv.Itoa(info.Likes))
+		printField("Comments", strconv.Itoa(info.Comments))
+		if info.Location != nil {
+			printField("Location", info.Location.Name)
+		}
+		printField("Caption", info.Caption)
+		printField("Hashtags", strings.Join(info.Hashtags, ", "))
+		printField("Mentions", strings.Join(info.Mentions, ", "))
+		printField("Tagged", strings.Join(info.TaggedUsers, ", "))
+		printField("Thumbnail", info.Thumbnail)
+
+	case "pinterest":
+		// Pins have no separate metadata lookup, the stream carries it.
+		res, err := kit.Pinterest.Stream(url)
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return printJSON(res)
+		}
+		printField("ID", res.ID)
+		printField("Title", res.Title)
+		printField("Thumbnail", res.Thumbnail)
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+// Command dlkit fetches metadata and media links from Spotify, YouTube,
+// Instagram and Pinterest, and downloads them.
+//
+// Usage:
+//
+//	dlkit info [--json] URL
+//	dlkit stream [--json] [--quality Q] [--provider NAME] URL
+//	dlkit search [--json] [--platform spotify|youtube] [--type T] [--limit N] QUERY
+//	dlkit download [--quality Q] [--provider NAME] [-o TEMPLATE] [--batch-file FILE] [URL...]
+//	dlkit serve [--addr :8080] [--api-key KEY] [--rate N] [--burst N] [--proxy] [--public-url URL]
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Beesonn/dlkitgo"
+)
+
+const (
+	exitOK              = 0
+	exitError           = 1
+	exitUsage           = 2
+	exitInvalidURL      = 3
+	exitNotFound        = 4
+	exitProvidersFailed = 5
+	exitLoginRequired   = 6
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{"info", "show metadata for a link", runInfo},
+	{"stream", "list the media URLs of a link", runStream},
+	{"search", "search Spotify or YouTube", runSearch},
+	{"download", "download links into files", runDownload},
+	{"serve", "run the HTTP API server", runServe},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		usage()
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+		err := cmd.run(args[1:])
+		if err == nil {
+			return exitOK
+		}
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		fmt.Fprintf(os.Stderr, "dlkit %s: %v\n", cmd.name, err)
+		return exitCode(err)
+	}
+
+	fmt.Fprintf(os.Stderr, "dlkit: unknown command %q\n\n", args[0])
+	usage()
+	return exitUsage
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: dlkit <command> [flags] [arguments]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Exit codes: 1 error, 2 usage, 3 invalid URL, 4 not found, 5 all providers failed.")
+	fmt.Fprintln(os.Stderr, "Run 'dlkit <command> -h' for the flags of a command.")
+}
+
+// codedError carries the exit code the CLI decided on itself.
+type codedError struct {
+	code int
+	err  error
+}
+
+func (e *codedError) Error() string { return e.err.Error() }
+func (e *codedError) Unwrap() error { return e.err }
+
+func withCode(code int, err error) error {
+	return &codedError{code: code, err: err}
+}
+
+// exitCode maps errors onto the documented exit codes.
+func exitCode(err error) int {
+	var coded *codedError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+	return classify(err)
+}
+
+func classify(err error) int {
+	return kindCode(dlkitgo.ErrorKind(err))
+}
+
+// kindCode maps a dlkitgo.ErrorKind result onto an exit code.
+func kindCode(kind string) int {
+	switch kind {
+	case dlkitgo.ErrKindInvalidURL:
+		return exitInvalidURL
+	case dlkitgo.ErrKindNotFound:
+		return exitNotFound
+	case dlkitgo.ErrKindProvidersFailed:
+		return exitProvidersFailed
+	case dlkitgo.ErrKindLoginRequired:
+		return exitLoginRequired
+	}
+	return exitError
+}
+
+// parseFlags parses fs while allowing flags after positional arguments,
+// as in "dlkit info URL --json".
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, withCode(exitUsage, err)
+		}
+		rest := fs.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
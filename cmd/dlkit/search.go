@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/Beesonn/dlkitgo"
+	"github.com/Beesonn/dlkitgo/spotify"
+)
+
+func runSearch(args []string) error {
+	fs := flag.NewFlagSet("search", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the results as JSON")
+	platform := fs.String("platform", "spotify", "where to search: spotify or youtube")
+	searchType := fs.String("type", "", "Spotify result types: track, album, artist, playlist, show or all")
+	limit := fs.Int("limit", 20, "maximum number of results")
+	offset := fs.Int("offset", 0, "Spotify result offset")
+	market := fs.String("market", "", "Spotify market, e.g. US")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	query := strings.TrimSpace(strings.Join(args, " "))
+	if query == "" {
+		return withCode(exitUsage, errors.New("search query cannot be empty"))
+	}
+
+	kit := dlkitgo.NewClient()
+
+	switch strings.ToLower(*platform) {
+	case "spotify":
+		res, err := kit.Spotify.SearchWithOptions(query, spotify.SearchOptions{
+			Type:   *searchType,
+			Limit:  *limit,
+			Offset: *offset,
+			Market: *market,
+		})
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return printJSON(res)
+		}
+		if len(res.Results) == 0 {
+			return withCode(exitNotFound, errors.New("no results"))
+		}
+		for _, r := range res.Results {
+			fmt.Printf("%-8s %s - %s\n  %s\n", r.Type, r.Artists, r.Name, r.URL)
+		}
+
+	case "youtube":
+		res, err := kit.Youtube.Search(query, *limit)
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return printJSON(res)
+		}
+		if len(res.Results) == 0 {
+			return withCode(exitNotFound, errors.New("no results"))
+		}
+		for _, r := range res.Results {
+			fmt.Printf("%s - %s (%s)\n  %s\n", r.Channel, r.Name, formatDuration(r.Duration), r.URL)
+		}
+
+	default:
+		return withCode(exitUsage, fmt.Errorf("cannot search %s", *platform))
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Beesonn/dlkitgo/proxy"
+	"github.com/Beesonn/dlkitgo/server"
+)
+
+type stringList []string
+
+func (l *stringList) String() string     { return strings.Join(*l, ",") }
+func (l *stringList) Set(v string) error { *l = append(*l, v); return nil }
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", ":8080", "address to listen on")
+	var keys stringList
+	fs.Var(&keys, "api-key", "accepted API key, may be repeated; DLKIT_API_KEYS adds comma separated keys")
+	keysFile := fs.String("keys-file", "", "file with one \"key [rate burst]\" per line")
+	rate := fs.Float64("rate", server.DefaultLimit.Rate, "requests per second allowed per key")
+	burst := fs.Int("burst", server.DefaultLimit.Burst, "request burst allowed per key")
+	enableProxy := fs.Bool("proxy", false, "enable /v1/proxy to stream media through the server")
+	publicURL := fs.String("public-url", "", "URL clients reach the server at; enables signed /v1/media links for Spotify downloads")
+	proxyKey := fs.String("proxy-key", "", "HMAC key for signed media links, DLKIT_PROXY_KEY by default; random when unset")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		return withCode(exitUsage, fmt.Errorf("unexpected argument %q", args[0]))
+	}
+
+	kit, err := newKit()
+	if err != nil {
+		return err
+	}
+	srv := server.New(kit)
+	srv.DefaultLimit = server.Limit{Rate: *rate, Burst: *burst}
+	srv.EnableProxy = *enableProxy
+
+	if *publicURL != "" {
+		key := *proxyKey
+		if key == "" {
+			key = os.Getenv("DLKIT_PROXY_KEY")
+		}
+		srv.Media = proxy.New(strings.TrimRight(*publicURL, "/")+"/v1/media", []byte(key))
+		srv.Kit.Spotify.SetProxyLink(srv.Media.Wrap)
+	}
+
+	if env := os.Getenv("DLKIT_API_KEYS"); env != "" {
+		keys = append(keys, strings.Split(env, ",")...)
+	}
+	for _, key := range keys {
+		if key = strings.TrimSpace(key); key != "" {
+			srv.APIKeys[key] = server.Limit{}
+		}
+	}
+	if *keysFile != "" {
+		if err := readKeysFile(*keysFile, srv.APIKeys); err != nil {
+			return err
+		}
+	}
+	if len(srv.APIKeys) == 0 {
+		fmt.Fprintln(os.Stderr, "warning: no API keys configured, the API is open to anyone who can reach it")
+	}
+
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           srv,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdown)
+	}()
+
+	fmt.Fprintf(os.Stderr, "dlkit listening on %s\n", *addr)
+	if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func readKeysFile(path string, keys map[string]server.Limit) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		var limit server.Limit
+		if len(fields) >= 3 {
+			limit.Rate, err = strconv.ParseFloat(fields[1], 64)
+			if err == nil {
+				limit.Burst, err = strconv.Atoi(fields[2])
+			}
+			if err != nil {
+				return fmt.Errorf("%s:%d: invalid rate limit", path, line)
+			}
+		}
+		keys[fields[0]] = limit
+	}
+	return scanner.Err()
+}
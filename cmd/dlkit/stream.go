@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// streamSource is the common shape of the platforms' stream sources, used
+// for --quality filtering and plain text output.
+type streamSource struct {
+	Type    string `json:"type"`
+	Quality string `json:"quality,omitempty"`
+	Title   string `json:"title,omitempty"`
+	URL     string `json:"url"`
+}
+
+func runStream(args []string) error {
+	fs := flag.NewFlagSet("stream", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the full result as JSON")
+	quality := fs.String("quality", "", "only list sources of this quality or type, e.g. 720p, 320kbps, audio, video")
+	provider := fs.String("provider", "", "use only this provider")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	url, err := oneURL(args)
+	if err != nil {
+		return err
+	}
+	platform, err := requirePlatform(url)
+	if err != nil {
+		return err
+	}
+
+	kit, err := newKit()
+	if err != nil {
+		return err
+	}
+	kit, err = useProvider(kit, platform, *provider)
+	if err != nil {
+		return err
+	}
+
+	var result any
+	var sources []streamSource
+
+	switch platform {
+	case "spotify":
+		res, err := kit.Spotify.Stream(url)
+		if err != nil {
+			return err
+		}
+		if len(res.Source) == 0 && len(res.Failed) > 0 {
+			return withCode(exitProvidersFailed, errors.New(res.Failed[0].Error))
+		}
+		for _, src := range res.Source {
+			sources = append(sources, streamSource{Type: "audio", Title: src.Artist + " - " + src.Title, URL: src.URL})
+		}
+		result = res
+
+	case "youtube":
+		res, err := kit.Youtube.Stream(url)
+		if err != nil {
+			return err
+		}
+		filtered := res.Source[:0]
+		for _, src := range res.Source {
+			if matchesQuality(src.Type, src.Quality, *quality) {
+				filtered = append(filtered, src)
+				sources = append(sources, streamSource{Type: src.Type, Quality: src.Quality, Title: res.Caption, URL: src.URL})
+			}
+		}
+		res.Source = filtered
+		result = res
+
+	case "instagram":
+		res, err := kit.Instagram.Stream(url)
+		if err != nil {
+			return err
+		}
+		filtered := res.Source[:0]
+		for _, src := range res.Source {
+			if matchesQuality(src.Type, "", *quality) {
+				filtered = append(filtered, src)
+				sources = append(sources, streamSource{Type: src.Type, URL: src.URL})
+			}
+		}
+		res.Source = filtered
+		result = res
+
+	case "pinterest":
+		res, err := kit.Pinterest.Stream(url)
+		if err != nil {
+			return err
+		}
+		filtered := res.Source[:0]
+		for _, src := range res.Source {
+			if matchesQuality(src.Type, src.Quality, *quality) {
+				filtered = append(filtered, src)
+				sources = append(sources, streamSource{Type: src.Type, Quality: src.Quality, Title: res.Title, URL: src.URL})
+			}
+		}
+		res.Source = filtered
+		result = res
+	}
+
+	if len(sources) == 0 {
+		if *quality != "" {
+			return fmt.Errorf("no source matches quality %q", *quality)
+		}
+		return withCode(exitProvidersFailed, errors.New("no stream sources available"))
+	}
+
+	if *asJSON {
+		return printJSON(result)
+	}
+	for _, src := range sources {
+		label := strings.TrimSpace(src.Type + " " + src.Quality)
+		if src.Title != "" {
+			label += "  " + src.Title
+		}
+		fmt.Printf("%s\n  %s\n", label, src.URL)
+	}
+	return nil
+}
+
+// matchesQuality accepts a source whose quality label or type equals
+// quality. An empty quality or "best" accepts everything.
+func matchesQuality(typ, label, quality string) bool {
+	quality = strings.TrimSpace(quality)
+	if quality == "" || strings.EqualFold(quality, "best") {
+		return true
+	}
+	return strings.EqualFold(label, quality) || strings.EqualFold(typ, quality)
+}
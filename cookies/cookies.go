@@ -0,0 +1,98 @@
+// Package cookies reads and writes Netscape cookies.txt files, the format
+// browser extensions and yt-dlp export, and keeps each service's cookies in
+// a jar of its own.
+package cookies
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Read parses the Netscape cookies.txt format. Cookies that include
+// subdomains get a Domain with a leading dot, host-only cookies one
+// without, the convention Write and Jar follow.
+func Read(r io.Reader) ([]*http.Cookie, error) {
+	var cookies []*http.Cookie
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		httpOnly := false
+		if strings.HasPrefix(line, "#HttpOnly_") {
+			line = strings.TrimPrefix(line, "#HttpOnly_")
+			httpOnly = true
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) == 6 {
+			// Some exporters drop the value of empty cookies.
+			fields = append(fields, "")
+		}
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("line %d: expected 7 tab separated fields, got %d", n, len(fields))
+		}
+
+		domain := strings.TrimPrefix(fields[0], ".")
+		if strings.EqualFold(fields[1], "TRUE") {
+			domain = "." + domain
+		}
+		c := &http.Cookie{
+			Domain:   domain,
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			Name:     fields[5],
+			Value:    fields[6],
+			HttpOnly: httpOnly,
+		}
+		if exp, err := strconv.ParseInt(fields[4], 10, 64); err == nil && exp > 0 {
+			c.Expires = time.Unix(exp, 0)
+		}
+		cookies = append(cookies, c)
+	}
+	return cookies, scanner.Err()
+}
+
+// Write writes cookies in the Netscape cookies.txt format. Session cookies
+// are written with an expiry of 0, and only cookies whose Domain starts
+// with a dot are marked as including subdomains.
+func Write(w io.Writer, cookies []*http.Cookie) error {
+	sorted := append([]*http.Cookie(nil), cookies...)
+	sort.Slice(sorted, func(a, b int) bool {
+		if sorted[a].Domain != sorted[b].Domain {
+			return sorted[a].Domain < sorted[b].Domain
+		}
+		return sorted[a].Name < sorted[b].Name
+	})
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("# Netscape HTTP Cookie File\n\n")
+	for _, c := range sorted {
+		domain := c.Domain
+		if c.HttpOnly {
+			domain = "#HttpOnly_" + domain
+		}
+		var exp int64
+		if !c.Expires.IsZero() {
+			exp = c.Expires.Unix()
+		}
+		fmt.Fprintf(bw, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			domain, upperBool(strings.HasPrefix(c.Domain, ".")), c.Path, upperBool(c.Secure), exp, c.Name, c.Value)
+	}
+	return bw.Flush()
+}
+
+func upperBool(b bool) string {
+	if b {
+		return "TRUE"
+	}
+	return "FALSE"
+}
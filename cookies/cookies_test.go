@@ -0,0 +1,119 @@
+package cookies
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const cookiesTxt = `# Netscape HTTP Cookie File
+# This is a generated file! Do not edit.
+
+.youtube.com	TRUE	/	TRUE	1893456000	PREF	f6=40000000
+#HttpOnly_.youtube.com	TRUE	/	TRUE	1893456000	SID	secret
+accounts.google.com	FALSE	/	FALSE	0	NID	abc
+.instagram.com	TRUE	/	TRUE	0	ds_user
+`
+
+func TestRead(t *testing.T) {
+	cookies, err := Read(strings.NewReader(cookiesTxt))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []http.Cookie{
+		{Domain: ".youtube.com", Path: "/", Secure: true, Expires: time.Unix(1893456000, 0), Name: "PREF", Value: "f6=40000000"},
+		{Domain: ".youtube.com", Path: "/", Secure: true, Expires: time.Unix(1893456000, 0), Name: "SID", Value: "secret", HttpOnly: true},
+		{Domain: "accounts.google.com", Path: "/", Name: "NID", Value: "abc"},
+		{Domain: ".instagram.com", Path: "/", Secure: true, Name: "ds_user"},
+	}
+	if len(cookies) != len(want) {
+		t.Fatalf("read %d cookies, want %d", len(cookies), len(want))
+	}
+	for i, c := range cookies {
+		w := want[i]
+		if c.Domain != w.Domain || c.Path != w.Path || c.Secure != w.Secure || c.HttpOnly != w.HttpOnly ||
+			!c.Expires.Equal(w.Expires) || c.Name != w.Name || c.Value != w.Value {
+			t.Errorf("cookie %d = %+v, want %+v", i, *c, w)
+		}
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	for _, in := range []string{
+		"youtube.com\tTRUE\t/\n",
+		"a\tb\tc\td\te\tf\tg\th\n",
+	} {
+		if _, err := Read(strings.NewReader(in)); err == nil {
+			t.Errorf("Read(%q) succeeded", in)
+		}
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	cookies, err := Read(strings.NewReader(cookiesTxt))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, cookies); err != nil {
+		t.Fatal(err)
+	}
+	want := `# Netscape HTTP Cookie File
+
+.instagram.com	TRUE	/	TRUE	0	ds_user	
+.youtube.com	TRUE	/	TRUE	1893456000	PREF	f6=40000000
+#HttpOnly_.youtube.com	TRUE	/	TRUE	1893456000	SID	secret
+accounts.google.com	FALSE	/	FALSE	0	NID	abc
+`
+	if buf.String() != want {
+		t.Errorf("Write = %q, want %q", buf.String(), want)
+	}
+
+	again, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again) != len(cookies) {
+		t.Errorf("read back %d cookies, want %d", len(again), len(cookies))
+	}
+}
+
+func TestJarHostOnly(t *testing.T) {
+	cookies, err := Read(strings.NewReader(cookiesTxt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	jar := New("")
+	jar.Add(cookies...)
+
+	for _, tc := range []struct {
+		url, name, want string
+	}{
+		{"https://www.youtube.com/", "PREF", "f6=40000000"},
+		{"https://accounts.google.com/", "NID", "abc"},
+		{"https://mail.accounts.google.com/", "NID", ""},
+	} {
+		u, _ := url.Parse(tc.url)
+		if got := jar.Value(u, tc.name); got != tc.want {
+			t.Errorf("%s: %s = %q, want %q", tc.url, tc.name, got, tc.want)
+		}
+	}
+
+	u, _ := url.Parse("https://www.instagram.com/accounts/login/")
+	jar.SetCookies(u, []*http.Cookie{
+		{Name: "csrftoken", Value: "x"},
+		{Name: "mid", Value: "y", Domain: "instagram.com"},
+	})
+	domains := map[string]string{}
+	for _, c := range jar.All() {
+		domains[c.Name] = c.Domain
+	}
+	if domains["csrftoken"] != "www.instagram.com" || domains["mid"] != ".instagram.com" {
+		t.Errorf("domains = %v", domains)
+	}
+}
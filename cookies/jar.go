@@ -0,0 +1,212 @@
+package cookies
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Jar is a cookie jar that keeps track of its cookies, so a login can be
+// written back to a cookies.txt file whenever a site refreshes it.
+type Jar struct {
+	// Path, when set, is rewritten whenever a response changes a cookie.
+	Path string
+
+	mu      sync.Mutex
+	jar     *cookiejar.Jar
+	entries map[string]*http.Cookie
+	// saveMu keeps concurrent saves from interleaving their renames.
+	saveMu sync.Mutex
+}
+
+// expiryDrift is how far a refreshed expiry may move before the file is
+// rewritten for it. Sites extend expiries on every response, which would
+// otherwise turn every request into a write.
+const expiryDrift = 24 * time.Hour
+
+// New returns an empty jar that persists to path, which may be empty.
+func New(path string) *Jar {
+	jar, _ := cookiejar.New(nil)
+	return &Jar{
+		Path:    path,
+		jar:     jar,
+		entries: map[string]*http.Cookie{},
+	}
+}
+
+// Load reads a cookies.txt file into a jar that keeps saving to it.
+func Load(path string) (*Jar, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	cookies, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	j := New(path)
+	j.Add(cookies...)
+	return j, nil
+}
+
+// Client returns a copy of client that uses jar, so the cookies never reach
+// whatever else shares client. A nil client is treated as an empty one.
+func Client(client *http.Client, jar http.CookieJar) *http.Client {
+	c := &http.Client{}
+	if client != nil {
+		*c = *client
+	}
+	c.Jar = jar
+	return c
+}
+
+func (j *Jar) Cookies(u *url.URL) []*http.Cookie {
+	return j.jar.Cookies(u)
+}
+
+func (j *Jar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	copies := make([]*http.Cookie, len(cookies))
+	for i, c := range cookies {
+		cc := *c
+		// A cookie without a Domain attribute is only sent back to the
+		// host that set it, which a Domain without a dot records.
+		if cc.Domain == "" {
+			cc.Domain = u.Hostname()
+		} else if !strings.HasPrefix(cc.Domain, ".") {
+			cc.Domain = "." + cc.Domain
+		}
+		if cc.Path == "" {
+			cc.Path = "/"
+		}
+		copies[i] = &cc
+	}
+	// Most responses only rotate cookies to the values they already had,
+	// so the file is only rewritten when something actually changed.
+	if changed := j.add(copies); j.Path != "" && changed {
+		// Persisting is best effort, the cookies still work in memory.
+		if err := j.Save(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to save cookies to %s: %v\n", j.Path, err)
+		}
+	}
+}
+
+// Add stores cookies as they are, without saving the jar. Each cookie
+// needs its Domain set, with a leading dot unless it is host-only.
+func (j *Jar) Add(cookies ...*http.Cookie) {
+	j.add(cookies)
+}
+
+// add is Add reporting whether the saved form of the jar changed.
+func (j *Jar) add(cookies []*http.Cookie) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	now := time.Now()
+	changed := false
+	for _, c := range cookies {
+		u := &url.URL{Scheme: "https", Host: strings.TrimPrefix(c.Domain, "."), Path: c.Path}
+		set := *c
+		if !strings.HasPrefix(c.Domain, ".") {
+			// The jar keeps cookies without a Domain to their host.
+			set.Domain = ""
+		}
+		j.jar.SetCookies(u, []*http.Cookie{&set})
+
+		key := c.Domain + ";" + c.Path + ";" + c.Name
+		old, exists := j.entries[key]
+		if c.MaxAge < 0 || (!c.Expires.IsZero() && c.Expires.Before(now)) {
+			if exists {
+				delete(j.entries, key)
+				changed = true
+			}
+			continue
+		}
+		stored := *c
+		if c.MaxAge > 0 {
+			stored.Expires = now.Add(time.Duration(c.MaxAge) * time.Second)
+			stored.MaxAge = 0
+		}
+		// Unchanged entries keep their old expiry, so small extensions
+		// add up until they are worth a write.
+		if exists && sameCookie(old, &stored) {
+			continue
+		}
+		j.entries[key] = &stored
+		changed = true
+	}
+	return changed
+}
+
+func sameCookie(a, b *http.Cookie) bool {
+	if a.Value != b.Value || a.Secure != b.Secure || a.HttpOnly != b.HttpOnly || a.Expires.IsZero() != b.Expires.IsZero() {
+		return false
+	}
+	drift := a.Expires.Sub(b.Expires)
+	return drift < expiryDrift && drift > -expiryDrift
+}
+
+// Value returns the value of the cookie name that would be sent to u, or
+// an empty string.
+func (j *Jar) Value(u *url.URL, name string) string {
+	for _, c := range j.jar.Cookies(u) {
+		if c.Name == name {
+			return c.Value
+		}
+	}
+	return ""
+}
+
+// All returns every unexpired cookie in the jar.
+func (j *Jar) All() []*http.Cookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	now := time.Now()
+	cookies := make([]*http.Cookie, 0, len(j.entries))
+	for _, c := range j.entries {
+		if c.Expires.IsZero() || c.Expires.After(now) {
+			cookies = append(cookies, c)
+		}
+	}
+	return cookies
+}
+
+// Save writes the jar to Path.
+func (j *Jar) Save() error {
+	if j.Path == "" {
+		return errors.New("no cookie file to save to")
+	}
+
+	j.saveMu.Lock()
+	defer j.saveMu.Unlock()
+
+	tmp, err := os.CreateTemp(filepath.Dir(j.Path), ".cookies-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := Write(tmp, j.All()); err != nil {
+		tmp.Close()
+		return err
+	}
+	// Cookies are credentials, keep them private.
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), j.Path)
+}
@@ -1,13 +1,20 @@
 package dlkitgo
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/Beesonn/dlkitgo/cookies"
 	"github.com/Beesonn/dlkitgo/instagram"
+	"github.com/Beesonn/dlkitgo/lyrics"
 	"github.com/Beesonn/dlkitgo/pinterest"
 	"github.com/Beesonn/dlkitgo/spotify"
+	"github.com/Beesonn/dlkitgo/tag"
 	"github.com/Beesonn/dlkitgo/youtube"
+
+	ytproviders "github.com/Beesonn/dlkitgo/youtube/providers"
 )
 
 type Dlkit struct {
@@ -16,6 +23,8 @@ type Dlkit struct {
 	Instagram *instagram.InstaService
 	Youtube   *youtube.TubeService
 	Pinterest *pinterest.PinService
+	Lyrics    *lyrics.LyricsService
+	Tag       *tag.TagService
 }
 
 func NewClient() *Dlkit {
@@ -27,6 +36,107 @@ func NewClient() *Dlkit {
 	c.Instagram = instagram.NewInsta(c.Client)
 	c.Youtube = youtube.NewTube(c.Client)
 	c.Pinterest = pinterest.NewPin(c.Client)
+	c.Lyrics = lyrics.NewLyrics(c.Client)
+	c.Tag = tag.NewTag(c.Client)
 
 	return c
 }
+
+// DetectPlatform names the service a link belongs to: spotify, youtube,
+// instagram or pinterest. Unknown links yield an empty string.
+func DetectPlatform(url string) string {
+	url = strings.TrimSpace(url)
+	switch {
+	case url == "":
+		return ""
+	case spotify.SpotifyURLRegex.MatchString(url), spotify.SpotifyURIRegex.MatchString(url), spotify.ShortLinkRegex.MatchString(url):
+		return "spotify"
+	case ytproviders.IsYouTubeURL(url):
+		return "youtube"
+	case instagram.InstagramURLPattern.MatchString(url), instagram.ShortcodeRegex.MatchString(url):
+		return "instagram"
+	case pinterest.PinIDRegex.MatchString(url), pinterest.ShortLinkRegex.MatchString(url):
+		return "pinterest"
+	}
+	return ""
+}
+
+// LoadCookies sends the requests of the service for platform with the
+// cookies in the Netscape cookies.txt file at path, and writes refreshed
+// cookies back to it. The service gets a client and jar of its own, so
+// the cookies never reach the other services.
+func (d *Dlkit) LoadCookies(platform, path string) error {
+	if platform == "instagram" {
+		return d.Instagram.LoadCookies(path)
+	}
+
+	jar, err := cookies.Load(path)
+	if err != nil {
+		return err
+	}
+	switch platform {
+	case "spotify":
+		d.Spotify.UseCookies(jar)
+	case "youtube":
+		d.Youtube.UseCookies(jar)
+	case "pinterest":
+		d.Pinterest.UseCookies(jar)
+	default:
+		return fmt.Errorf("unsupported platform: %s", platform)
+	}
+	return nil
+}
+
+type namedProvider interface {
+	Name() string
+}
+
+// WithProvider returns a copy of d whose service for platform only uses
+// the named provider. d itself is left untouched, so it stays safe to use
+// from other goroutines.
+func (d *Dlkit) WithProvider(platform, name string) (*Dlkit, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return d, nil
+	}
+
+	c := *d
+	var err error
+	switch platform {
+	case "spotify":
+		svc := spotify.NewSpotify(d.Client)
+		svc.Workers, svc.HostLimit, svc.SearchProxyURL = d.Spotify.Workers, d.Spotify.HostLimit, d.Spotify.SearchProxyURL
+		svc.Client = d.Spotify.Client
+		svc.Providers, err = pickProvider(d.Spotify.Providers, name)
+		c.Spotify = svc
+	case "youtube":
+		svc := *d.Youtube
+		svc.Providers, err = pickProvider(d.Youtube.Providers, name)
+		c.Youtube = &svc
+	case "instagram":
+		svc := *d.Instagram
+		svc.Providers, err = pickProvider(d.Instagram.Providers, name)
+		c.Instagram = &svc
+	case "pinterest":
+		svc := *d.Pinterest
+		svc.Providers, err = pickProvider(d.Pinterest.Providers, name)
+		c.Pinterest = &svc
+	default:
+		err = fmt.Errorf("unsupported platform: %s", platform)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func pickProvider[P namedProvider](providers []P, name string) ([]P, error) {
+	names := make([]string, 0, len(providers))
+	for _, p := range providers {
+		if strings.EqualFold(p.Name(), name) {
+			return []P{p}, nil
+		}
+		names = append(names, p.Name())
+	}
+	return nil, fmt.Errorf("unknown provider %q, available: %s", name, strings.Join(names, ", "))
+}
@@ -0,0 +1,37 @@
+// Package errkind holds the sentinel errors the services wrap, so callers
+// can sort failures with errors.Is instead of reading messages. It imports
+// nothing from this module, which lets every service package use it.
+package errkind
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidURL means the link is not one the service understands.
+	ErrInvalidURL = errors.New("invalid URL")
+	// ErrNotFound means the platform answered that the item does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrLoginRequired means the platform only serves the item to a
+	// logged in session.
+	ErrLoginRequired = errors.New("login required")
+	// ErrProvidersFailed means no configured provider could serve the link.
+	ErrProvidersFailed = errors.New("all providers failed")
+)
+
+// New returns an error with the message format produces that errors.Is
+// matches against kind as well as anything format wraps with %w. The
+// message itself does not mention kind.
+func New(kind error, format string, args ...any) error {
+	return &kindError{kind: kind, err: fmt.Errorf(format, args...)}
+}
+
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string { return e.err.Error() }
+
+func (e *kindError) Unwrap() []error { return []error{e.kind, e.err} }
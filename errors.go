@@ -0,0 +1,41 @@
+package dlkitgo
+
+import (
+	"errors"
+
+	"github.com/Beesonn/dlkitgo/errkind"
+)
+
+const (
+	ErrKindInvalidURL      = "invalid_url"
+	ErrKindNotFound        = "not_found"
+	ErrKindLoginRequired   = "login_required"
+	ErrKindProvidersFailed = "providers_failed"
+)
+
+// The sentinel errors the services wrap, for use with errors.Is.
+var (
+	ErrInvalidURL      = errkind.ErrInvalidURL
+	ErrNotFound        = errkind.ErrNotFound
+	ErrLoginRequired   = errkind.ErrLoginRequired
+	ErrProvidersFailed = errkind.ErrProvidersFailed
+)
+
+// ErrorKind sorts an error returned by the services into one of the
+// ErrKind constants, or an empty string for anything else, such as network
+// failures.
+func ErrorKind(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrInvalidURL):
+		return ErrKindInvalidURL
+	case errors.Is(err, ErrNotFound):
+		return ErrKindNotFound
+	case errors.Is(err, ErrLoginRequired):
+		return ErrKindLoginRequired
+	case errors.Is(err, ErrProvidersFailed):
+		return ErrKindProvidersFailed
+	}
+	return ""
+}
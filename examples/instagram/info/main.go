@@ -17,6 +17,8 @@ func main() {
 	fmt.Printf("Username: %s\n", info.Username)
 	fmt.Printf("Caption: %s\n", info.Caption)
 	fmt.Printf("Thumbnail: %s\n", info.Thumbnail)
-	fmt.Printf("Date: %s\n", info.Date)
-	fmt.Printf("Comments: %s\n", info.Comments)
+	fmt.Printf("Date: %s\n", info.TakenAt.Format("2006-01-02"))
+	fmt.Printf("Likes: %d\n", info.Likes)
+	fmt.Printf("Comments: %d\n", info.Comments)
+	fmt.Printf("Hashtags: %v\n", info.Hashtags)
 }
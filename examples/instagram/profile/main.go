@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Beesonn/dlkitgo"
+	"github.com/Beesonn/dlkitgo/instagram"
+)
+
+func main() {
+	client := dlkitgo.NewClient()
+	ctx := context.Background()
+
+	profile, err := client.Instagram.Profile(ctx, "nasa")
+	if err != nil {
+		fmt.Println("ERROR: Profile failed:", err)
+		return
+	}
+
+	fmt.Printf("Username: %s\n", profile.Username)
+	fmt.Printf("Bio: %s\n", profile.Biography)
+	fmt.Printf("Followers: %d\n", profile.Followers)
+	fmt.Printf("Following: %d\n", profile.Following)
+	fmt.Printf("Posts: %d\n", profile.Posts)
+	fmt.Printf("Picture: %s\n", profile.PictureHD)
+
+	n := 0
+	for shortcode, err := range profile.IterPosts(ctx) {
+		if err != nil {
+			fmt.Println("ERROR: Listing posts failed:", err)
+			break
+		}
+		fmt.Println("Post:", instagram.PostURL(shortcode))
+		if n++; n == 5 {
+			break
+		}
+	}
+
+	for highlight, err := range profile.IterHighlights(ctx) {
+		if err != nil {
+			fmt.Println("ERROR: Listing highlights failed:", err)
+			break
+		}
+		fmt.Printf("Highlight: %s %s\n", highlight.Title, highlight.URL)
+	}
+}
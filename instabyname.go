@@ -2,6 +2,9 @@ package dlkitgo
 
 import "fmt"
 
+// InstaByName streams a fixed reel through the thesocialcat provider and prints it.
+//
+// Deprecated: use "dlkit stream" instead.
 func InstaByName() {
 	client := NewClient()
 	url := "https://www.instagram.com/reel/DKrA73pIjFn"
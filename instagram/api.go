@@ -0,0 +1,87 @@
+package instagram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Beesonn/dlkitgo/errkind"
+	"github.com/Beesonn/dlkitgo/instagram/providers"
+)
+
+const (
+	WebBaseURL = "https://www.instagram.com"
+	WebAppID   = providers.WebAppID
+	UserAgent  = providers.UserAgent
+)
+
+// APIGet fetches path from the Instagram web API and decodes the JSON
+// response into v.
+func (insta *InstaService) APIGet(ctx context.Context, path string, query url.Values, v interface{}) error {
+	apiURL := WebBaseURL + path
+	if len(query) > 0 {
+		apiURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	return insta.doAPI(req, v)
+}
+
+// APIPost sends form to path on the Instagram web API and decodes the JSON
+// response into v.
+func (insta *InstaService) APIPost(ctx context.Context, path string, form url.Values, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", WebBaseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return insta.doAPI(req, v)
+}
+
+func (insta *InstaService) doAPI(req *http.Request, v interface{}) error {
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-IG-App-ID", WebAppID)
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+	req.Header.Set("Referer", WebBaseURL+"/")
+	if token := providers.CSRFToken(insta.Client, req.URL); token != "" {
+		req.Header.Set("X-CSRFToken", token)
+	}
+
+	resp, err := insta.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return errkind.New(errkind.ErrNotFound, "API error: %d not found", resp.StatusCode)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return errkind.New(errkind.ErrLoginRequired, "API error: %d, Instagram requires a login for this request", resp.StatusCode)
+	default:
+		return fmt.Errorf("API error: %d", resp.StatusCode)
+	}
+
+	// Logged out requests that hit a login wall get the login page instead
+	// of JSON with a 200 status.
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if !strings.Contains(resp.Header.Get("Content-Type"), "json") && !json.Valid(body) {
+		return errkind.New(errkind.ErrLoginRequired, "API error: Instagram requires a login for this request")
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package instagram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Beesonn/dlkitgo/errkind"
+)
+
+type Comment struct {
+	ID       string    `json:"id"`
+	ParentID string    `json:"parent_id,omitempty"`
+	Author   string    `json:"author"`
+	AuthorID string    `json:"author_id"`
+	Text     string    `json:"text"`
+	Created  time.Time `json:"created"`
+	Likes    int       `json:"likes"`
+	// ReplyCount is the number of replies Instagram reports, which may be
+	// more than Replies holds when some are hidden.
+	ReplyCount int       `json:"reply_count,omitempty"`
+	Replies    []Comment `json:"replies,omitempty"`
+}
+
+type apiComment struct {
+	PK               json.Number `json:"pk"`
+	Text             string      `json:"text"`
+	CreatedAt        int64       `json:"created_at"`
+	CommentLikeCount int         `json:"comment_like_count"`
+	ChildCount       int         `json:"child_comment_count"`
+	ParentID         json.Number `json:"parent_comment_id"`
+	User             struct {
+		PK       json.Number `json:"pk"`
+		Username string      `json:"username"`
+	} `json:"user"`
+	PreviewChildComments []apiComment `json:"preview_child_comments"`
+}
+
+type apiCommentPage struct {
+	Comments  []apiComment `json:"comments"`
+	NextMinID string       `json:"next_min_id"`
+	NextMaxID string       `json:"next_max_id"`
+}
+
+type apiChildCommentPage struct {
+	ChildComments []apiComment `json:"child_comments"`
+	HasMore       bool         `json:"has_more_tail_child_comments"`
+	NextCursor    string       `json:"next_max_child_cursor"`
+}
+
+const shortcodeAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+
+// MediaID converts a shortcode to the numeric media ID the API expects.
+// Private posts have longer shortcodes whose first 11 characters are the ID.
+func MediaID(shortcode string) (string, error) {
+	if len(shortcode) > 11 {
+		shortcode = shortcode[:11]
+	}
+	if shortcode == "" {
+		return "", errkind.New(errkind.ErrInvalidURL, "no shortcode in Instagram URL")
+	}
+
+	id := new(big.Int)
+	for _, c := range shortcode {
+		n := strings.IndexRune(shortcodeAlphabet, c)
+		if n < 0 {
+			return "", errkind.New(errkind.ErrInvalidURL, "invalid Instagram shortcode")
+		}
+		id.Lsh(id, 6)
+		id.Or(id, big.NewInt(int64(n)))
+	}
+	return id.String(), nil
+}
+
+// Comments yields the comments of a post or reel page by page, in the order
+// Instagram ranks them. Each comment carries all of its replies. When the
+// replies of a comment cannot be loaded, often because they need a login,
+// the comment is yielded with the replies Instagram previews, followed by
+// the error, and iteration goes on with the next comment.
+func (insta *InstaService) Comments(ctx context.Context, postURL string) iter.Seq2[Comment, error] {
+	return func(yield func(Comment, error) bool) {
+		shortcode, err := Shortcode(postURL)
+		if err != nil {
+			yield(Comment{}, err)
+			return
+		}
+		mediaID, err := MediaID(shortcode)
+		if err != nil {
+			yield(Comment{}, err)
+			return
+		}
+
+		path := "/api/v1/media/" + mediaID + "/comments/"
+		query := url.Values{}
+		query.Set("can_support_threading", "true")
+		query.Set("permalink_enabled", "false")
+
+		for {
+			var page apiCommentPage
+			if err := insta.APIGet(ctx, path, query, &page); err != nil {
+				yield(Comment{}, err)
+				return
+			}
+
+			for _, c := range page.Comments {
+				comment := c.comment()
+				var repliesErr error
+				if c.ChildCount > len(c.PreviewChildComments) {
+					replies, err := insta.replies(ctx, mediaID, comment.ID)
+					if len(replies) > len(comment.Replies) {
+						comment.Replies = replies
+					}
+					if err != nil {
+						repliesErr = fmt.Errorf("failed to load replies to comment %s: %w", comment.ID, err)
+					}
+				}
+				if !yield(comment, nil) {
+					return
+				}
+				if repliesErr != nil && !yield(Comment{}, repliesErr) {
+					return
+				}
+			}
+
+			// The web app pages forward with min_id, older clients with
+			// max_id.
+			query.Del("min_id")
+			query.Del("max_id")
+			switch {
+			case page.NextMinID != "":
+				query.Set("min_id", page.NextMinID)
+			case page.NextMaxID != "":
+				query.Set("max_id", page.NextMaxID)
+			default:
+				return
+			}
+			if len(page.Comments) == 0 {
+				return
+			}
+		}
+	}
+}
+
+func (insta *InstaService) replies(ctx context.Context, mediaID, commentID string) ([]Comment, error) {
+	path := "/api/v1/media/" + mediaID + "/comments/" + commentID + "/child_comments/"
+	query := url.Values{}
+
+	var replies []Comment
+	for {
+		var page apiChildCommentPage
+		if err := insta.APIGet(ctx, path, query, &page); err != nil {
+			return replies, err
+		}
+		for _, c := range page.ChildComments {
+			reply := c.comment()
+			reply.ParentID = commentID
+			replies = append(replies, reply)
+		}
+		if !page.HasMore || page.NextCursor == "" || len(page.ChildComments) == 0 {
+			return replies, nil
+		}
+		query.Set("max_id", page.NextCursor)
+	}
+}
+
+func (c apiComment) comment() Comment {
+	comment := Comment{
+		ID:         c.PK.String(),
+		ParentID:   c.ParentID.String(),
+		Author:     c.User.Username,
+		AuthorID:   c.User.PK.String(),
+		Text:       c.Text,
+		Likes:      c.CommentLikeCount,
+		ReplyCount: c.ChildCount,
+	}
+	if c.CreatedAt > 0 {
+		comment.Created = time.Unix(c.CreatedAt, 0).UTC()
+	}
+	for _, child := range c.PreviewChildComments {
+		reply := child.comment()
+		if reply.ParentID == "" {
+			reply.ParentID = comment.ID
+		}
+		comment.Replies = append(comment.Replies, reply)
+	}
+	return comment
+}
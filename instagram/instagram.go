@@ -4,16 +4,22 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
 	"strings"
 
+	"github.com/Beesonn/dlkitgo/cookies"
+	"github.com/Beesonn/dlkitgo/errkind"
 	"github.com/Beesonn/dlkitgo/instagram/providers"
 )
 
 type InstaService struct {
 	Client        *http.Client
 	Providers     []Provider
+	Web           Provider
 	FastVideoSave Provider
 	TheSocialCat  Provider
+	// Session holds the login cookies set by LoadCookies or SetSessionID.
+	Session *cookies.Jar
 }
 
 func NewInsta(client *http.Client) *InstaService {
@@ -24,7 +30,9 @@ func NewInsta(client *http.Client) *InstaService {
 	service.Providers = DefaultProviders(client)
 
 	for _, provider := range service.Providers {
-		if provider.Name() == "fastvideosave" {
+		if provider.Name() == "instagram" {
+			service.Web = provider
+		} else if provider.Name() == "fastvideosave" {
 			service.FastVideoSave = provider
 		} else if provider.Name() == "thesocialcat" {
 			service.TheSocialCat = provider
@@ -39,7 +47,6 @@ func (i *InstaService) GetProvider(name string) (Provider, error) {
 		return nil, errors.New("please provide the provider name")
 	}
 	for _, provider := range i.Providers {
-		fmt.Println(provider.Name())
 		if provider.Name() == strings.ToLower(strings.TrimSpace(name)) {
 			return provider, nil
 		}
@@ -51,24 +58,44 @@ func (i *InstaService) Stream(url string) (providers.InstaStreamResult, error) {
 	if url == "" {
 		return providers.InstaStreamResult{}, errors.New("url cannot be empty")
 	}
-	info, err := i.GetInfo(url)
-	if err != nil {
-		return providers.InstaStreamResult{}, errors.New("Invalid Instagram URL")
+	if !InstagramURLPattern.MatchString(url) {
+		return providers.InstaStreamResult{}, errkind.New(errkind.ErrInvalidURL, "Invalid Instagram URL")
 	}
 
-	for _, provider := range i.Providers {
-		res, err := provider.Stream(url)
-
+	// The page is only fetched when a provider leaves out the caption or
+	// the username, which Instagram's own provider never does.
+	var info *InstagramData
+	fill := func(res *providers.InstaStreamResult) {
+		if res.Caption != "" && res.Username != "" && res.Shortcode != "" {
+			return
+		}
+		if info == nil {
+			data, _ := i.GetInfo(url)
+			info = &data
+		}
 		if res.Caption == "" {
 			res.Caption = info.Caption
 		}
 		if res.Username == "" {
 			res.Username = info.Username
 		}
+		if res.Shortcode == "" {
+			res.Shortcode = info.Shortcode
+		}
+	}
+
+	kind := ContentKind(url)
+	list := i.ProvidersFor(kind)
+	if len(list) == 0 {
+		return providers.InstaStreamResult{}, errkind.New(errkind.ErrProvidersFailed, "no configured provider supports %s links", kind)
+	}
+	for _, provider := range list {
+		res, err := provider.Stream(url)
 		if err == nil {
+			fill(&res)
 			return res, nil
 		}
-		fmt.Printf("Provider '%s' failed to stream: %v\n", provider.Name(), err)
+		fmt.Fprintf(os.Stderr, "Provider '%s' failed to stream: %v\n", provider.Name(), err)
 	}
-	return providers.InstaStreamResult{}, errors.New("all configured providers failed to stream the content")
+	return providers.InstaStreamResult{}, errkind.New(errkind.ErrProvidersFailed, "all configured providers failed to stream the content")
 }
@@ -1,33 +1,97 @@
 package instagram
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/Beesonn/dlkitgo/errkind"
+	"github.com/Beesonn/dlkitgo/instagram/providers"
 	"github.com/PuerkitoBio/goquery"
 )
 
 type InstagramData struct {
+	Shortcode string `json:"shortcode"`
 	Username  string `json:"username"`
-	Likes     string `json:"likes"`
-	Comments  string `json:"comments"`
+	FullName  string `json:"full_name,omitempty"`
+	Likes     int    `json:"likes"`
+	Comments  int    `json:"comments"`
+	Views     int    `json:"views,omitempty"`
 	Caption   string `json:"caption"`
-	Date      string `json:"date"`
-	Thumbnail string `json:"thumbnail"`
+	// Hashtags and Mentions are taken from the caption, without their
+	// leading # and @.
+	Hashtags    []string  `json:"hashtags,omitempty"`
+	Mentions    []string  `json:"mentions,omitempty"`
+	TakenAt     time.Time `json:"taken_at,omitzero"`
+	Location    *Location `json:"location,omitempty"`
+	TaggedUsers []string  `json:"tagged_users,omitempty"`
+	Thumbnail   string    `json:"thumbnail"`
 }
 
+type Location = providers.Location
+
 var (
-	InstagramURLPattern = regexp.MustCompile(`^https?://(?:www\.)?instagram\.com/(?:p|reel|stories)/[a-zA-Z0-9_-]+`)
-	LikesRegex          = regexp.MustCompile(`^([0-9Kk\.,]+) likes`)
-	CommentsRegex       = regexp.MustCompile(`,\s*([0-9Kk\.,]+)\s*comments`)
-	UserRegex           = regexp.MustCompile(`-\s*(.*?)\s*on`)
-	DateRegex           = regexp.MustCompile(`on\s(.*?):`)
-	CaptionRegex        = regexp.MustCompile(`:\s*"(.*)"`)
+	InstagramURLPattern = regexp.MustCompile(`^https?://(?:www\.)?instagram\.com/(?:(?:[^/?#]+/)?(?:p|reels?|tv)|stories)/[a-zA-Z0-9_-]+`)
+	ShortcodeRegex      = regexp.MustCompile(`instagram\.com/(?:[^/?#]+/)?(?:p|reel|reels|tv)/([a-zA-Z0-9_-]+)`)
+	HashtagRegex        = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_&/])#([\p{L}\p{N}_]+)`)
+	MentionRegex        = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_./])@([A-Za-z0-9._]{1,30})`)
+
+	// The og:description patterns are only used when a page carries no
+	// JSON. They expect the English "N likes, N comments - user on date:
+	// caption" layout.
+	LikesRegex    = regexp.MustCompile(`^([0-9KkMm\.,]+) likes?`)
+	CommentsRegex = regexp.MustCompile(`,\s*([0-9KkMm\.,]+)\s*comments?`)
+	UserRegex     = regexp.MustCompile(`-\s*([A-Za-z0-9._]+)\s+on\s`)
+	DateRegex     = regexp.MustCompile(`-\s*[A-Za-z0-9._]+\s+on\s+([A-Z][a-z]+ \d{1,2}, \d{4})`)
+	CaptionRegex  = regexp.MustCompile(`(?s)\d{4}:\s*"(.*)"\.?\s*$`)
 )
 
+// webInfoKey precedes the media of a post page in its embedded JSON.
+const webInfoKey = `"xdt_api__v1__media__shortcode__web_info":`
+
+// apiMediaItem is a post as the v1 API and the post page JSON describe it.
+type apiMediaItem struct {
+	Code         string `json:"code"`
+	TakenAt      int64  `json:"taken_at"`
+	LikeCount    int    `json:"like_count"`
+	CommentCount int    `json:"comment_count"`
+	PlayCount    int    `json:"play_count"`
+	ViewCount    int    `json:"view_count"`
+	Caption      *struct {
+		Text string `json:"text"`
+	} `json:"caption"`
+	User struct {
+		Username string `json:"username"`
+		FullName string `json:"full_name"`
+	} `json:"user"`
+	Location *struct {
+		PK   json.Number `json:"pk"`
+		Name string      `json:"name"`
+		Slug string      `json:"slug"`
+	} `json:"location"`
+	Usertags       *apiUsertags `json:"usertags"`
+	ImageVersions2 struct {
+		Candidates []apiImageVersion `json:"candidates"`
+	} `json:"image_versions2"`
+	CarouselMedia []struct {
+		Usertags *apiUsertags `json:"usertags"`
+	} `json:"carousel_media"`
+}
+
+type apiUsertags struct {
+	In []struct {
+		User struct {
+			Username string `json:"username"`
+		} `json:"user"`
+	} `json:"in"`
+}
+
 func (insta *InstaService) GetInfo(url string) (InstagramData, error) {
 	var data InstagramData
 
@@ -36,7 +100,17 @@ func (insta *InstaService) GetInfo(url string) (InstagramData, error) {
 	}
 
 	if !InstagramURLPattern.MatchString(url) {
-		return data, errors.New("Invalid Instagram URL")
+		return data, errkind.New(errkind.ErrInvalidURL, "Invalid Instagram URL")
+	}
+
+	// Instagram's own data has exact counts, so the page is only read when
+	// no provider can read the post directly.
+	for _, provider := range insta.Providers {
+		if mp, ok := provider.(MediaProvider); ok {
+			if post, err := mp.Media(url); err == nil {
+				return DataFromPost(post), nil
+			}
+		}
 	}
 
 	req, err := http.NewRequest("GET", url, nil)
@@ -44,7 +118,7 @@ func (insta *InstaService) GetInfo(url string) (InstagramData, error) {
 		return data, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/114.0.0.0 Safari/537.36")
+	req.Header.Set("User-Agent", UserAgent)
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8")
 	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
 	req.Header.Set("Sec-Fetch-Dest", "document")
@@ -54,32 +128,171 @@ func (insta *InstaService) GetInfo(url string) (InstagramData, error) {
 
 	resp, err := insta.Client.Do(req)
 	if err != nil {
-		return data, errors.New("Invalid Instagram URL")
+		return data, fmt.Errorf("failed to fetch %s: %w", url, err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return data, errors.New("Invalid Instagram URL")
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return data, errkind.New(errkind.ErrNotFound, "Instagram post not found")
+	default:
+		return data, fmt.Errorf("Instagram returned status %d", resp.StatusCode)
 	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return data, fmt.Errorf("failed to parse HTML: %w", err)
+		return data, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	data = insta.ExtractInstagramData(doc)
+	if page, ok := ParsePageJSON(string(body)); ok {
+		data = page
+	} else {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(body)))
+		if err != nil {
+			return data, fmt.Errorf("failed to parse HTML: %w", err)
+		}
+		data = insta.ExtractInstagramData(doc)
+		data.Hashtags, data.Mentions = CaptionTags(data.Caption)
+	}
+	if data.Shortcode == "" {
+		data.Shortcode, _ = Shortcode(url)
+	}
 	return data, nil
 }
 
-func (insta *InstaService) ExtractInstagramData(doc *goquery.Document) InstagramData {
+// Shortcode returns the ID Instagram uses for a post or reel in its URLs.
+func Shortcode(url string) (string, error) {
+	if m := ShortcodeRegex.FindStringSubmatch(url); m != nil {
+		return m[1], nil
+	}
+	return "", errkind.New(errkind.ErrInvalidURL, "no shortcode in Instagram URL")
+}
+
+// DataFromPost converts a post read by a MediaProvider.
+func DataFromPost(post providers.Post) InstagramData {
 	data := InstagramData{
-		Username:  "",
-		Comments:  "",
-		Caption:   "",
-		Date:      "",
-		Thumbnail: "",
+		Shortcode:   post.Shortcode,
+		Username:    post.Owner.Username,
+		FullName:    post.Owner.FullName,
+		Likes:       post.Likes,
+		Comments:    post.Comments,
+		Views:       post.Views,
+		Caption:     post.Caption,
+		TakenAt:     post.TakenAt,
+		Location:    post.Location,
+		TaggedUsers: post.TaggedUsers,
+		Thumbnail:   post.Thumbnail,
+	}
+	data.Hashtags, data.Mentions = CaptionTags(data.Caption)
+	return data
+}
+
+// ParsePageJSON reads the media a post page embeds for its own scripts.
+func ParsePageJSON(page string) (InstagramData, bool) {
+	start := strings.Index(page, webInfoKey)
+	if start < 0 {
+		return InstagramData{}, false
 	}
 
+	var info struct {
+		Items []apiMediaItem `json:"items"`
+	}
+	// Decode stops after the object, the rest of the page is ignored.
+	dec := json.NewDecoder(strings.NewReader(page[start+len(webInfoKey):]))
+	if err := dec.Decode(&info); err != nil || len(info.Items) == 0 {
+		return InstagramData{}, false
+	}
+	return dataFromItem(info.Items[0]), true
+}
+
+func dataFromItem(item apiMediaItem) InstagramData {
+	data := InstagramData{
+		Shortcode: item.Code,
+		Username:  item.User.Username,
+		FullName:  item.User.FullName,
+		Likes:     item.LikeCount,
+		Comments:  item.CommentCount,
+		Views:     max(item.PlayCount, item.ViewCount),
+	}
+	if item.Caption != nil {
+		data.Caption = item.Caption.Text
+	}
+	if item.TakenAt > 0 {
+		data.TakenAt = time.Unix(item.TakenAt, 0).UTC()
+	}
+	if item.Location != nil && item.Location.Name != "" {
+		data.Location = &Location{ID: item.Location.PK.String(), Name: item.Location.Name, Slug: item.Location.Slug}
+	}
+	if len(item.ImageVersions2.Candidates) > 0 {
+		data.Thumbnail = item.ImageVersions2.Candidates[0].URL
+	}
+
+	tags := []*apiUsertags{item.Usertags}
+	for _, child := range item.CarouselMedia {
+		tags = append(tags, child.Usertags)
+	}
+	seen := map[string]bool{}
+	for _, t := range tags {
+		if t == nil {
+			continue
+		}
+		for _, in := range t.In {
+			if name := in.User.Username; name != "" && !seen[name] {
+				seen[name] = true
+				data.TaggedUsers = append(data.TaggedUsers, name)
+			}
+		}
+	}
+
+	data.Hashtags, data.Mentions = CaptionTags(data.Caption)
+	return data
+}
+
+// CaptionTags returns the distinct hashtags and mentions in caption, in the
+// order they first appear.
+func CaptionTags(caption string) (hashtags, mentions []string) {
+	seen := map[string]bool{}
+	for _, m := range HashtagRegex.FindAllStringSubmatch(caption, -1) {
+		if key := "#" + strings.ToLower(m[1]); !seen[key] {
+			seen[key] = true
+			hashtags = append(hashtags, m[1])
+		}
+	}
+	for _, m := range MentionRegex.FindAllStringSubmatch(caption, -1) {
+		name := strings.TrimRight(m[1], ".")
+		if key := "@" + strings.ToLower(name); name != "" && !seen[key] {
+			seen[key] = true
+			mentions = append(mentions, name)
+		}
+	}
+	return hashtags, mentions
+}
+
+// ParseCount reads counts such as "1,234", "1.2K" or "3M".
+func ParseCount(s string) int {
+	s = strings.TrimSpace(strings.ReplaceAll(s, ",", ""))
+	if s == "" {
+		return 0
+	}
+
+	mult := 1.0
+	switch s[len(s)-1] {
+	case 'k', 'K':
+		mult, s = 1e3, s[:len(s)-1]
+	case 'm', 'M':
+		mult, s = 1e6, s[:len(s)-1]
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return int(n*mult + 0.5)
+}
+
+func (insta *InstaService) ExtractInstagramData(doc *goquery.Document) InstagramData {
+	data := InstagramData{}
+
 	doc.Find("meta").Each(func(i int, s *goquery.Selection) {
 		if property, exists := s.Attr("property"); exists {
 			if property == "og:image" {
@@ -98,9 +311,15 @@ func (insta *InstaService) ExtractInstagramData(doc *goquery.Document) Instagram
 	return data
 }
 
+// ParseMetaDescription reads an English og:description. Other locales only
+// yield what happens to match.
 func (insta *InstaService) ParseMetaDescription(descText string, data *InstagramData) {
+	if matches := LikesRegex.FindStringSubmatch(descText); len(matches) > 1 {
+		data.Likes = ParseCount(matches[1])
+	}
+
 	if matches := CommentsRegex.FindStringSubmatch(descText); len(matches) > 1 {
-		data.Comments = matches[1]
+		data.Comments = ParseCount(matches[1])
 	}
 
 	if matches := UserRegex.FindStringSubmatch(descText); len(matches) > 1 {
@@ -108,15 +327,12 @@ func (insta *InstaService) ParseMetaDescription(descText string, data *Instagram
 	}
 
 	if matches := DateRegex.FindStringSubmatch(descText); len(matches) > 1 {
-		data.Date = matches[1]
+		if t, err := time.Parse("January 2, 2006", matches[1]); err == nil {
+			data.TakenAt = t
+		}
 	}
 
 	if matches := CaptionRegex.FindStringSubmatch(descText); len(matches) > 1 {
 		data.Caption = matches[1]
-	} else if strings.Contains(descText, ":") {
-		parts := strings.SplitN(descText, ":", 2)
-		if len(parts) > 1 {
-			data.Caption = strings.Trim(strings.TrimSpace(parts[1]), `"`)
-		}
 	}
 }
@@ -0,0 +1,128 @@
+package instagram
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestInstagramURLPattern(t *testing.T) {
+	for _, tc := range []struct {
+		url       string
+		match     bool
+		shortcode string
+	}{
+		{"https://www.instagram.com/p/C1a2B3c4D5e/", true, "C1a2B3c4D5e"},
+		{"https://instagram.com/p/C1a2B3c4D5e", true, "C1a2B3c4D5e"},
+		{"https://www.instagram.com/reel/C1a2B3c4D5e/?igsh=abc", true, "C1a2B3c4D5e"},
+		{"https://www.instagram.com/reels/C1a2B3c4D5e/", true, "C1a2B3c4D5e"},
+		{"https://www.instagram.com/tv/C1a2B3c4D5e/", true, "C1a2B3c4D5e"},
+		{"https://www.instagram.com/natgeo/p/C1a2B3c4D5e/", true, "C1a2B3c4D5e"},
+		{"https://www.instagram.com/natgeo/reel/C1a2B3c4D5e/", true, "C1a2B3c4D5e"},
+		{"https://www.instagram.com/stories/natgeo/3141592653589793238/", true, ""},
+		{"https://www.instagram.com/stories/highlights/17912345678901234/", true, ""},
+		{"https://www.instagram.com/natgeo/", false, ""},
+		{"https://www.instagram.com/explore/tags/cats/", false, ""},
+		{"https://example.com/p/C1a2B3c4D5e/", false, ""},
+	} {
+		if got := InstagramURLPattern.MatchString(tc.url); got != tc.match {
+			t.Errorf("%s: match = %v, want %v", tc.url, got, tc.match)
+		}
+		if got, _ := Shortcode(tc.url); got != tc.shortcode {
+			t.Errorf("%s: Shortcode = %q, want %q", tc.url, got, tc.shortcode)
+		}
+	}
+}
+
+func TestCaptionTags(t *testing.T) {
+	for _, tc := range []struct {
+		caption  string
+		hashtags []string
+		mentions []string
+	}{
+		{"", nil, nil},
+		{"#sunset at the beach with @Alice", []string{"sunset"}, []string{"Alice"}},
+		{"#Cats #cats #CATS and #café", []string{"Cats", "café"}, nil},
+		{"thanks @bob.smith. and @bob.smith!", nil, []string{"bob.smith"}},
+		{"mail me at me@example.com or see example.com/#anchor, R&#38;D", nil, nil},
+		{"#one,#two\n@three_3", []string{"one", "two"}, []string{"three_3"}},
+	} {
+		hashtags, mentions := CaptionTags(tc.caption)
+		if !slices.Equal(hashtags, tc.hashtags) {
+			t.Errorf("%q: hashtags = %q, want %q", tc.caption, hashtags, tc.hashtags)
+		}
+		if !slices.Equal(mentions, tc.mentions) {
+			t.Errorf("%q: mentions = %q, want %q", tc.caption, mentions, tc.mentions)
+		}
+	}
+}
+
+func TestParseCount(t *testing.T) {
+	for in, want := range map[string]int{
+		"":      0,
+		"42":    42,
+		"1,234": 1234,
+		"1.2K":  1200,
+		"3m":    3000000,
+		"n/a":   0,
+	} {
+		if got := ParseCount(in); got != want {
+			t.Errorf("ParseCount(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestParsePageJSON(t *testing.T) {
+	page := `<script>{"data":{"xdt_api__v1__media__shortcode__web_info":{"items":[{
+		"code": "C1a2B3c4D5e",
+		"taken_at": 1700000000,
+		"like_count": 120,
+		"comment_count": 7,
+		"play_count": 900,
+		"caption": {"text": "Out west #travel with @carol"},
+		"user": {"username": "dave", "full_name": "Dave"},
+		"location": {"pk": 123456, "name": "Monument Valley", "slug": "monument-valley"},
+		"usertags": {"in": [{"user": {"username": "erin"}}]},
+		"image_versions2": {"candidates": [{"url": "https://cdn.example.com/thumb.jpg"}]},
+		"carousel_media": [
+			{"usertags": {"in": [{"user": {"username": "erin"}}, {"user": {"username": "frank"}}]}},
+			{"usertags": null}
+		]
+	}]}}}</script><script>var other = {};</script>`
+
+	data, ok := ParsePageJSON(page)
+	if !ok {
+		t.Fatal("ParsePageJSON found no post")
+	}
+	if data.Shortcode != "C1a2B3c4D5e" || data.Username != "dave" || data.FullName != "Dave" {
+		t.Errorf("post = %q by %q (%q)", data.Shortcode, data.Username, data.FullName)
+	}
+	if data.Likes != 120 || data.Comments != 7 || data.Views != 900 {
+		t.Errorf("counts = %d likes, %d comments, %d views", data.Likes, data.Comments, data.Views)
+	}
+	if want := time.Unix(1700000000, 0).UTC(); !data.TakenAt.Equal(want) {
+		t.Errorf("TakenAt = %v, want %v", data.TakenAt, want)
+	}
+	if data.Location == nil || data.Location.ID != "123456" || data.Location.Name != "Monument Valley" || data.Location.Slug != "monument-valley" {
+		t.Errorf("Location = %+v", data.Location)
+	}
+	if !slices.Equal(data.TaggedUsers, []string{"erin", "frank"}) {
+		t.Errorf("TaggedUsers = %q", data.TaggedUsers)
+	}
+	if !slices.Equal(data.Hashtags, []string{"travel"}) || !slices.Equal(data.Mentions, []string{"carol"}) {
+		t.Errorf("Hashtags = %q, Mentions = %q", data.Hashtags, data.Mentions)
+	}
+	if data.Thumbnail != "https://cdn.example.com/thumb.jpg" {
+		t.Errorf("Thumbnail = %q", data.Thumbnail)
+	}
+
+	for _, page := range []string{
+		"<html></html>",
+		`"xdt_api__v1__media__shortcode__web_info":{"items":[]}`,
+		`"xdt_api__v1__media__shortcode__web_info":{"items":[`,
+	} {
+		if _, ok := ParsePageJSON(page); ok {
+			t.Errorf("ParsePageJSON(%q) found a post", page)
+		}
+	}
+}
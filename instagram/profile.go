@@ -0,0 +1,309 @@
+package instagram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Beesonn/dlkitgo/errkind"
+)
+
+type Profile struct {
+	ID          string `json:"id"`
+	Username    string `json:"username"`
+	FullName    string `json:"full_name"`
+	Biography   string `json:"biography"`
+	ExternalURL string `json:"external_url,omitempty"`
+	Followers   int    `json:"followers"`
+	Following   int    `json:"following"`
+	Posts       int    `json:"posts"`
+	Highlights  int    `json:"highlights"`
+	Picture     string `json:"picture"`
+	PictureHD   string `json:"picture_hd"`
+	Private     bool   `json:"private"`
+	Verified    bool   `json:"verified"`
+
+	service *InstaService
+	// recent are the shortcodes embedded in the profile response, used
+	// when the feed itself needs a login.
+	recent []string
+}
+
+type Highlight struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	Cover      string `json:"cover"`
+	MediaCount int    `json:"media_count"`
+	URL        string `json:"url"`
+}
+
+var (
+	UsernameRegex   = regexp.MustCompile(`^@?([A-Za-z0-9._]{1,30})$`)
+	ProfileURLRegex = regexp.MustCompile(`^https?://(?:www\.)?instagram\.com/([A-Za-z0-9._]{1,30})/?(?:[?#].*)?$`)
+)
+
+// ProfilePageSize is the number of posts or reels requested per page.
+var ProfilePageSize = 12
+
+type apiProfile struct {
+	Data struct {
+		User *struct {
+			ID                string   `json:"id"`
+			Username          string   `json:"username"`
+			FullName          string   `json:"full_name"`
+			Biography         string   `json:"biography"`
+			ExternalURL       string   `json:"external_url"`
+			ProfilePicURL     string   `json:"profile_pic_url"`
+			ProfilePicURLHD   string   `json:"profile_pic_url_hd"`
+			IsPrivate         bool     `json:"is_private"`
+			IsVerified        bool     `json:"is_verified"`
+			HighlightCount    int      `json:"highlight_reel_count"`
+			EdgeFollowedBy    apiCount `json:"edge_followed_by"`
+			EdgeFollow        apiCount `json:"edge_follow"`
+			EdgeTimelineMedia struct {
+				Count int `json:"count"`
+				Edges []struct {
+					Node struct {
+						Shortcode string `json:"shortcode"`
+					} `json:"node"`
+				} `json:"edges"`
+			} `json:"edge_owner_to_timeline_media"`
+		} `json:"user"`
+	} `json:"data"`
+}
+
+type apiCount struct {
+	Count int `json:"count"`
+}
+
+type apiFeedPage struct {
+	Items []struct {
+		Code string `json:"code"`
+	} `json:"items"`
+	MoreAvailable bool   `json:"more_available"`
+	NextMaxID     string `json:"next_max_id"`
+}
+
+type apiClipsPage struct {
+	Items []struct {
+		Media struct {
+			Code string `json:"code"`
+		} `json:"media"`
+	} `json:"items"`
+	PagingInfo struct {
+		MaxID         string `json:"max_id"`
+		MoreAvailable bool   `json:"more_available"`
+	} `json:"paging_info"`
+}
+
+type apiHighlightTray struct {
+	Tray []struct {
+		ID         string `json:"id"`
+		Title      string `json:"title"`
+		MediaCount int    `json:"media_count"`
+		CoverMedia struct {
+			CroppedImageVersion struct {
+				URL string `json:"url"`
+			} `json:"cropped_image_version"`
+		} `json:"cover_media"`
+	} `json:"tray"`
+}
+
+// Username returns the account name in a profile URL. Plain usernames,
+// with or without a leading @, are returned as they are.
+func Username(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if m := ProfileURLRegex.FindStringSubmatch(s); m != nil {
+		switch m[1] {
+		case "p", "reel", "reels", "tv", "stories", "explore", "accounts":
+		default:
+			return m[1], nil
+		}
+	}
+	if m := UsernameRegex.FindStringSubmatch(s); m != nil {
+		return m[1], nil
+	}
+	return "", errkind.New(errkind.ErrInvalidURL, "invalid Instagram username")
+}
+
+// Profile fetches the public details of an account. username may also be
+// a profile URL.
+func (insta *InstaService) Profile(ctx context.Context, username string) (*Profile, error) {
+	name, err := Username(username)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("username", name)
+
+	var res apiProfile
+	if err := insta.APIGet(ctx, "/api/v1/users/web_profile_info/", query, &res); err != nil {
+		return nil, err
+	}
+	u := res.Data.User
+	if u == nil {
+		return nil, errkind.New(errkind.ErrNotFound, "profile %s not found", name)
+	}
+
+	profile := &Profile{
+		ID:          u.ID,
+		Username:    u.Username,
+		FullName:    u.FullName,
+		Biography:   u.Biography,
+		ExternalURL: u.ExternalURL,
+		Followers:   u.EdgeFollowedBy.Count,
+		Following:   u.EdgeFollow.Count,
+		Posts:       u.EdgeTimelineMedia.Count,
+		Highlights:  u.HighlightCount,
+		Picture:     u.ProfilePicURL,
+		PictureHD:   u.ProfilePicURLHD,
+		Private:     u.IsPrivate,
+		Verified:    u.IsVerified,
+		service:     insta,
+	}
+	if profile.PictureHD == "" {
+		profile.PictureHD = profile.Picture
+	}
+	for _, edge := range u.EdgeTimelineMedia.Edges {
+		if edge.Node.Shortcode != "" {
+			profile.recent = append(profile.recent, edge.Node.Shortcode)
+		}
+	}
+
+	return profile, nil
+}
+
+// URL returns the profile page of the account.
+func (p *Profile) URL() string {
+	return fmt.Sprintf("%s/%s/", WebBaseURL, p.Username)
+}
+
+// IterPosts yields the shortcode of every post, newest first, page by
+// page. Feed pages need a login for most accounts; without one only the
+// posts embedded in the profile are yielded, followed by the error.
+func (p *Profile) IterPosts(ctx context.Context) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		if p.service == nil || p.ID == "" {
+			yield("", errors.New("profile was not loaded with InstaService.Profile"))
+			return
+		}
+
+		for maxID, first := "", true; ; first = false {
+			query := url.Values{}
+			query.Set("count", strconv.Itoa(ProfilePageSize))
+			if maxID != "" {
+				query.Set("max_id", maxID)
+			}
+
+			var page apiFeedPage
+			if err := p.service.APIGet(ctx, "/api/v1/feed/user/"+p.ID+"/", query, &page); err != nil {
+				if first {
+					for _, code := range p.recent {
+						if !yield(code, nil) {
+							return
+						}
+					}
+				}
+				yield("", err)
+				return
+			}
+
+			for _, item := range page.Items {
+				if item.Code == "" {
+					continue
+				}
+				if !yield(item.Code, nil) {
+					return
+				}
+			}
+
+			if !page.MoreAvailable || page.NextMaxID == "" {
+				return
+			}
+			maxID = page.NextMaxID
+		}
+	}
+}
+
+// IterReels yields the shortcode of every reel, newest first, page by page.
+func (p *Profile) IterReels(ctx context.Context) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		if p.service == nil || p.ID == "" {
+			yield("", errors.New("profile was not loaded with InstaService.Profile"))
+			return
+		}
+
+		for maxID := ""; ; {
+			form := url.Values{}
+			form.Set("target_user_id", p.ID)
+			form.Set("page_size", strconv.Itoa(ProfilePageSize))
+			form.Set("include_feed_video", "true")
+			if maxID != "" {
+				form.Set("max_id", maxID)
+			}
+
+			var page apiClipsPage
+			if err := p.service.APIPost(ctx, "/api/v1/clips/user/", form, &page); err != nil {
+				yield("", err)
+				return
+			}
+
+			for _, item := range page.Items {
+				if item.Media.Code == "" {
+					continue
+				}
+				if !yield(item.Media.Code, nil) {
+					return
+				}
+			}
+
+			if !page.PagingInfo.MoreAvailable || page.PagingInfo.MaxID == "" {
+				return
+			}
+			maxID = page.PagingInfo.MaxID
+		}
+	}
+}
+
+// IterHighlights yields the highlight reels pinned to the profile. Pass
+// their ID to InstaService.Highlight for the media.
+func (p *Profile) IterHighlights(ctx context.Context) iter.Seq2[Highlight, error] {
+	return func(yield func(Highlight, error) bool) {
+		if p.service == nil || p.ID == "" {
+			yield(Highlight{}, errors.New("profile was not loaded with InstaService.Profile"))
+			return
+		}
+
+		var tray apiHighlightTray
+		if err := p.service.APIGet(ctx, "/api/v1/highlights/"+p.ID+"/highlights_tray/", nil, &tray); err != nil {
+			yield(Highlight{}, err)
+			return
+		}
+
+		for _, item := range tray.Tray {
+			id := strings.TrimPrefix(item.ID, "highlight:")
+			h := Highlight{
+				ID:         id,
+				Title:      item.Title,
+				Cover:      item.CoverMedia.CroppedImageVersion.URL,
+				MediaCount: item.MediaCount,
+				URL:        HighlightURL(id),
+			}
+			if !yield(h, nil) {
+				return
+			}
+		}
+	}
+}
+
+// PostURL returns the link Stream expects for a shortcode yielded by
+// IterPosts or IterReels.
+func PostURL(shortcode string) string {
+	return fmt.Sprintf("%s/p/%s/", WebBaseURL, shortcode)
+}
@@ -2,7 +2,9 @@ package instagram
 
 import (
 	"net/http"
+	"regexp"
 
+	"github.com/Beesonn/dlkitgo/capability"
 	"github.com/Beesonn/dlkitgo/instagram/providers"
 )
 
@@ -12,9 +14,42 @@ type Provider interface {
 	Stream(url string) (providers.InstaStreamResult, error)
 }
 
+// MediaProvider is implemented by providers that read a post's metadata
+// from Instagram itself.
+type MediaProvider interface {
+	Media(url string) (providers.Post, error)
+}
+
+var ContentKindRegex = regexp.MustCompile(`instagram\.com/(?:[^/?#]+/)?(p|reels?|tv|stories)/([^/?#]+)`)
+
 func DefaultProviders(client *http.Client) []Provider {
 	return []Provider{
+		&providers.Web{Client: client},
 		&providers.FastVideoSave{Client: client},
 		&providers.TheSocialCat{Client: client},
 	}
 }
+
+// ContentKind returns the capability kind of an Instagram link, or an
+// empty string if it is not a post, reel, story or highlight.
+func ContentKind(url string) string {
+	m := ContentKindRegex.FindStringSubmatch(url)
+	if m == nil {
+		return ""
+	}
+	switch m[1] {
+	case "p":
+		return capability.Post
+	case "reel", "reels", "tv":
+		return capability.Reel
+	}
+	if m[2] == "highlights" {
+		return capability.Highlight
+	}
+	return capability.Story
+}
+
+// ProvidersFor returns the configured providers that support kind.
+func (i *InstaService) ProvidersFor(kind string) []Provider {
+	return capability.Filter(i.Providers, kind)
+}
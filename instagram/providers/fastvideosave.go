@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+
+	"github.com/Beesonn/dlkitgo/capability"
 )
 
 type FastVideoSave struct {
@@ -34,6 +36,13 @@ func (p *FastVideoSave) Post() bool {
 	return true
 }
 
+func (p *FastVideoSave) Capabilities() capability.Capabilities {
+	return capability.Capabilities{
+		Kinds:    kinds(p),
+		Carousel: true,
+	}
+}
+
 func (p *FastVideoSave) EncodeURL(text string) (string, error) {
 	key := []byte("qwertyuioplkjhgf")
 
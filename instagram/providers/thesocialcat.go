@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+
+	"github.com/Beesonn/dlkitgo/capability"
 )
 
 type TheSocialCat struct {
@@ -33,6 +35,13 @@ func (p *TheSocialCat) Post() bool {
 	return true
 }
 
+func (p *TheSocialCat) Capabilities() capability.Capabilities {
+	return capability.Capabilities{
+		Kinds:    kinds(p),
+		Carousel: true,
+	}
+}
+
 func (p *TheSocialCat) Stream(url string) (InstaStreamResult, error) {
 	result := InstaStreamResult{
 		Caption:  "",
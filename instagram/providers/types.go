@@ -1,17 +1,48 @@
 package providers
 
+import (
+	"time"
+
+	"github.com/Beesonn/dlkitgo/capability"
+)
+
 type MediaSource struct {
 	URL       string `json:"url"`
 	Type      string `json:"type"`
 	Thumbnail string `json:"thumbnail"`
 	Index     int    `json:"index"`
+	// TakenAt and ExpiresAt are only known for stories. Highlights do not
+	// expire.
+	TakenAt   time.Time `json:"taken_at,omitzero"`
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
 }
 
 type InstaStreamResult struct {
-	Caption  string        `json:"caption"`
-	Username string        `json:"username"`
-	Total    int           `json:"total"`
-	Video    int           `json:"video"`
-	Photo    int           `json:"photo"`
-	Source   []MediaSource `json:"source"`
+	Shortcode string        `json:"shortcode,omitempty"`
+	Caption   string        `json:"caption"`
+	Username  string        `json:"username"`
+	Total     int           `json:"total"`
+	Video     int           `json:"video"`
+	Photo     int           `json:"photo"`
+	Source    []MediaSource `json:"source"`
+}
+
+// kinds turns the Reel, Story and Post flags into content kinds. Providers
+// that download stories can download highlights as well.
+func kinds(p interface {
+	Reel() bool
+	Story() bool
+	Post() bool
+}) []string {
+	var list []string
+	if p.Post() {
+		list = append(list, capability.Post)
+	}
+	if p.Reel() {
+		list = append(list, capability.Reel)
+	}
+	if p.Story() {
+		list = append(list, capability.Story, capability.Highlight)
+	}
+	return list
 }
@@ -0,0 +1,489 @@
+package providers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Beesonn/dlkitgo/capability"
+	"github.com/Beesonn/dlkitgo/errkind"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Web reads posts and reels from Instagram itself, first through the web
+// GraphQL API and then through the public embed page, so it needs no
+// third-party site.
+type Web struct {
+	Client *http.Client
+}
+
+// Post is a post or reel as Instagram reports it.
+type Post struct {
+	Shortcode string    `json:"shortcode"`
+	Type      string    `json:"type"`
+	Caption   string    `json:"caption"`
+	Likes     int       `json:"likes"`
+	Comments  int       `json:"comments"`
+	Views     int       `json:"views,omitempty"`
+	TakenAt   time.Time `json:"taken_at"`
+	Owner     Owner     `json:"owner"`
+	Location  *Location `json:"location,omitempty"`
+	// TaggedUsers are the usernames tagged in the media itself.
+	TaggedUsers []string      `json:"tagged_users,omitempty"`
+	Thumbnail   string        `json:"thumbnail"`
+	Source      []MediaSource `json:"source"`
+
+	// Node is the media object the post was read from.
+	Node *GraphMedia `json:"-"`
+}
+
+type Owner struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	FullName string `json:"full_name,omitempty"`
+	Picture  string `json:"picture,omitempty"`
+	Verified bool   `json:"verified,omitempty"`
+}
+
+type Location struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug,omitempty"`
+}
+
+// GraphMedia is the shortcode_media object of the web GraphQL API and the
+// embed page.
+type GraphMedia struct {
+	Typename         string          `json:"__typename"`
+	ID               string          `json:"id"`
+	Shortcode        string          `json:"shortcode"`
+	DisplayURL       string          `json:"display_url"`
+	DisplayResources []GraphResource `json:"display_resources"`
+	IsVideo          bool            `json:"is_video"`
+	VideoURL         string          `json:"video_url"`
+	VideoVersions    []GraphResource `json:"video_versions"`
+	VideoViewCount   int             `json:"video_view_count"`
+	VideoPlayCount   int             `json:"video_play_count"`
+	TakenAt          int64           `json:"taken_at_timestamp"`
+	Owner            struct {
+		ID            string `json:"id"`
+		Username      string `json:"username"`
+		FullName      string `json:"full_name"`
+		ProfilePicURL string `json:"profile_pic_url"`
+		IsVerified    bool   `json:"is_verified"`
+	} `json:"owner"`
+	Location *struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		Slug string `json:"slug"`
+	} `json:"location"`
+	TaggedUsers struct {
+		Edges []struct {
+			Node struct {
+				User struct {
+					Username string `json:"username"`
+				} `json:"user"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"edge_media_to_tagged_user"`
+	Caption        GraphCaption `json:"edge_media_to_caption"`
+	PreviewLikes   GraphCount   `json:"edge_media_preview_like"`
+	LikedBy        GraphCount   `json:"edge_liked_by"`
+	Comments       GraphCount   `json:"edge_media_to_comment"`
+	ParentComments GraphCount   `json:"edge_media_to_parent_comment"`
+	Children       struct {
+		Edges []struct {
+			Node GraphMedia `json:"node"`
+		} `json:"edges"`
+	} `json:"edge_sidecar_to_children"`
+}
+
+type GraphCaption struct {
+	Edges []GraphCaptionEdge `json:"edges"`
+}
+
+type GraphCaptionEdge struct {
+	Node struct {
+		Text string `json:"text"`
+	} `json:"node"`
+}
+
+type GraphResource struct {
+	Src    string `json:"src"`
+	URL    string `json:"url"`
+	Width  int    `json:"config_width"`
+	Height int    `json:"config_height"`
+	// Video versions use plain width and height.
+	VideoWidth int `json:"width"`
+}
+
+type GraphCount struct {
+	Count int `json:"count"`
+}
+
+const (
+	// ShortcodeMediaDocID is the persisted GraphQL query that returns
+	// xdt_shortcode_media.
+	ShortcodeMediaDocID = "8845758582119845"
+	// WebAppID identifies the Instagram web app. The private web API
+	// rejects requests without it.
+	WebAppID  = "936619743392459"
+	UserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+)
+
+var (
+	webShortcodeRegex = regexp.MustCompile(`instagram\.com/(?:[^/?#]+/)?(?:p|reels?|tv)/([a-zA-Z0-9_-]+)`)
+	contextJSONRegex  = regexp.MustCompile(`"contextJSON":("(?:[^"\\]|\\.)*")`)
+	additionalRegex   = regexp.MustCompile(`window\.__additionalDataLoaded\(\s*[^,]+,\s*(\{.+?\})\s*\);`)
+)
+
+// client returns p.Client, or http.DefaultClient when it is nil. The
+// provider is shared between goroutines, so it is never assigned to.
+func (p *Web) client() *http.Client {
+	if p.Client == nil {
+		return http.DefaultClient
+	}
+	return p.Client
+}
+
+// CSRFToken returns the csrftoken cookie a logged in client sends with u.
+// Instagram rejects authenticated requests that do not echo it.
+func CSRFToken(client *http.Client, u *url.URL) string {
+	if client == nil || client.Jar == nil {
+		return ""
+	}
+	for _, c := range client.Jar.Cookies(u) {
+		if c.Name == "csrftoken" {
+			return c.Value
+		}
+	}
+	return ""
+}
+
+func (p *Web) Name() string {
+	return "instagram"
+}
+
+func (p *Web) BaseURL() string {
+	return "https://www.instagram.com"
+}
+
+func (p *Web) Reel() bool {
+	return true
+}
+
+func (p *Web) Story() bool {
+	return false
+}
+
+func (p *Web) Post() bool {
+	return true
+}
+
+func (p *Web) Capabilities() capability.Capabilities {
+	return capability.Capabilities{
+		Kinds:    kinds(p),
+		Carousel: true,
+	}
+}
+
+func (p *Web) Stream(url string) (InstaStreamResult, error) {
+	post, err := p.Media(url)
+	if err != nil {
+		return InstaStreamResult{}, err
+	}
+
+	result := InstaStreamResult{
+		Shortcode: post.Shortcode,
+		Caption:   post.Caption,
+		Username:  post.Owner.Username,
+		Source:    post.Source,
+	}
+	for _, src := range post.Source {
+		if src.Type == "video" {
+			result.Video++
+		} else {
+			result.Photo++
+		}
+	}
+	result.Total = result.Video + result.Photo
+	return result, nil
+}
+
+// Media returns the post behind url. The GraphQL API is asked first; the
+// embed page is used when it refuses, as it often does without a login.
+func (p *Web) Media(url string) (Post, error) {
+	if url == "" {
+		return Post{}, errors.New("url cannot be empty")
+	}
+	m := webShortcodeRegex.FindStringSubmatch(url)
+	if m == nil {
+		return Post{}, errkind.New(errkind.ErrInvalidURL, "no shortcode in Instagram URL")
+	}
+	shortcode := m[1]
+
+	node, err := p.GraphQL(shortcode)
+	if err != nil {
+		var embedErr error
+		node, embedErr = p.Embed(shortcode)
+		if embedErr != nil {
+			return Post{}, fmt.Errorf("graphql: %v, embed: %v", err, embedErr)
+		}
+	}
+	return node.Post(), nil
+}
+
+// GraphQL runs the xdt_shortcode_media query for shortcode.
+func (p *Web) GraphQL(shortcode string) (*GraphMedia, error) {
+	variables, err := json.Marshal(map[string]interface{}{
+		"shortcode":               shortcode,
+		"fetch_tagged_user_count": nil,
+		"hoisted_comment_id":      nil,
+		"hoisted_reply_id":        nil,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal variables: %w", err)
+	}
+
+	query := url.Values{}
+	query.Set("doc_id", ShortcodeMediaDocID)
+	query.Set("variables", string(variables))
+
+	req, err := http.NewRequest("GET", p.BaseURL()+"/graphql/query/?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("Accept", "*/*")
+	req.Header.Set("X-IG-App-ID", WebAppID)
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+	req.Header.Set("Referer", fmt.Sprintf("%s/p/%s/", p.BaseURL(), shortcode))
+	if token := CSRFToken(p.client(), req.URL); token != "" {
+		req.Header.Set("X-CSRFToken", token)
+	}
+
+	resp, err := p.client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GraphQL error: %d", resp.StatusCode)
+	}
+
+	var res struct {
+		Data struct {
+			Media *GraphMedia `json:"xdt_shortcode_media"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+	if res.Data.Media == nil {
+		return nil, errkind.New(errkind.ErrNotFound, "post not found or not public")
+	}
+	return res.Data.Media, nil
+}
+
+// Embed reads shortcode from the captioned embed page, which is served
+// without a login for public posts.
+func (p *Web) Embed(shortcode string) (*GraphMedia, error) {
+	embedURL := fmt.Sprintf("%s/p/%s/embed/captioned/", p.BaseURL(), shortcode)
+	req, err := http.NewRequest("GET", embedURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+
+	resp, err := p.client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("embed page error: %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if node := ParseEmbedJSON(string(body)); node != nil {
+		if node.Shortcode == "" {
+			node.Shortcode = shortcode
+		}
+		return node, nil
+	}
+	return ParseEmbedHTML(string(body), shortcode)
+}
+
+// ParseEmbedJSON returns the media object embedded in an embed page, or
+// nil when the page carries none.
+func ParseEmbedJSON(page string) *GraphMedia {
+	var blobs []string
+	if m := contextJSONRegex.FindStringSubmatch(page); m != nil {
+		var raw string
+		if err := json.Unmarshal([]byte(m[1]), &raw); err == nil {
+			blobs = append(blobs, raw)
+		}
+	}
+	if m := additionalRegex.FindStringSubmatch(page); m != nil {
+		blobs = append(blobs, m[1])
+	}
+
+	for _, blob := range blobs {
+		var data struct {
+			Context struct {
+				Media *GraphMedia `json:"media"`
+			} `json:"context"`
+			GQLData struct {
+				Media *GraphMedia `json:"shortcode_media"`
+			} `json:"gql_data"`
+			Media   *GraphMedia `json:"shortcode_media"`
+			GraphQL struct {
+				Media *GraphMedia `json:"shortcode_media"`
+			} `json:"graphql"`
+		}
+		if err := json.Unmarshal([]byte(blob), &data); err != nil {
+			continue
+		}
+		for _, node := range []*GraphMedia{data.GQLData.Media, data.Context.Media, data.Media, data.GraphQL.Media} {
+			if node != nil && (node.DisplayURL != "" || node.VideoURL != "" || len(node.Children.Edges) > 0) {
+				return node
+			}
+		}
+	}
+	return nil
+}
+
+// ParseEmbedHTML reads the single image and caption an embed page shows
+// when it carries no JSON. Videos and carousels need the JSON.
+func ParseEmbedHTML(page, shortcode string) (*GraphMedia, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	image, _ := doc.Find("img.EmbeddedMediaImage").Attr("src")
+	if image == "" {
+		return nil, errors.New("no media found on embed page")
+	}
+
+	node := &GraphMedia{
+		Typename:   "GraphImage",
+		Shortcode:  shortcode,
+		DisplayURL: image,
+	}
+	node.Owner.Username = strings.TrimSpace(doc.Find(".UsernameText").First().Text())
+
+	caption := doc.Find(".Caption").First().Clone()
+	caption.Find(".CaptionUsername, .CaptionComments").Remove()
+	if text := strings.TrimSpace(caption.Text()); text != "" {
+		var edge GraphCaptionEdge
+		edge.Node.Text = text
+		node.Caption.Edges = append(node.Caption.Edges, edge)
+	}
+	return node, nil
+}
+
+// Post flattens the media object and its carousel children.
+func (m *GraphMedia) Post() Post {
+	post := Post{
+		Shortcode: m.Shortcode,
+		Type:      mediaType(m),
+		Likes:     max(m.PreviewLikes.Count, m.LikedBy.Count),
+		Comments:  max(m.ParentComments.Count, m.Comments.Count),
+		Views:     max(m.VideoViewCount, m.VideoPlayCount),
+		Thumbnail: m.DisplayURL,
+		Owner: Owner{
+			ID:       m.Owner.ID,
+			Username: m.Owner.Username,
+			FullName: m.Owner.FullName,
+			Picture:  m.Owner.ProfilePicURL,
+			Verified: m.Owner.IsVerified,
+		},
+		Node: m,
+	}
+	if len(m.Caption.Edges) > 0 {
+		post.Caption = m.Caption.Edges[0].Node.Text
+	}
+	if m.TakenAt > 0 {
+		post.TakenAt = time.Unix(m.TakenAt, 0).UTC()
+	}
+	if m.Location != nil && m.Location.Name != "" {
+		post.Location = &Location{ID: m.Location.ID, Name: m.Location.Name, Slug: m.Location.Slug}
+	}
+
+	items := []*GraphMedia{m}
+	if len(m.Children.Edges) > 0 {
+		items = items[:0]
+		for n := range m.Children.Edges {
+			items = append(items, &m.Children.Edges[n].Node)
+		}
+	}
+	// Carousel items carry their own tags besides those of the post.
+	for _, item := range append([]*GraphMedia{m}, items...) {
+		for _, edge := range item.TaggedUsers.Edges {
+			if name := edge.Node.User.Username; name != "" && !slices.Contains(post.TaggedUsers, name) {
+				post.TaggedUsers = append(post.TaggedUsers, name)
+			}
+		}
+	}
+
+	for _, item := range items {
+		src := MediaSource{
+			URL:       bestImage(item),
+			Type:      "photo",
+			Thumbnail: item.DisplayURL,
+			Index:     len(post.Source),
+		}
+		if item.IsVideo {
+			src.Type = "video"
+			src.URL = bestVideo(item)
+		}
+		if src.URL != "" {
+			post.Source = append(post.Source, src)
+		}
+	}
+	return post
+}
+
+func mediaType(m *GraphMedia) string {
+	switch {
+	case len(m.Children.Edges) > 0:
+		return "carousel"
+	case m.IsVideo:
+		return "video"
+	}
+	return "photo"
+}
+
+func bestImage(m *GraphMedia) string {
+	best, width := m.DisplayURL, 0
+	for _, r := range m.DisplayResources {
+		if r.Src != "" && r.Width > width {
+			best, width = r.Src, r.Width
+		}
+	}
+	return best
+}
+
+func bestVideo(m *GraphMedia) string {
+	best, width := m.VideoURL, 0
+	for _, v := range m.VideoVersions {
+		if v.URL != "" && v.VideoWidth > width {
+			best, width = v.URL, v.VideoWidth
+		}
+	}
+	return best
+}
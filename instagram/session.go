@@ -0,0 +1,83 @@
+package instagram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Beesonn/dlkitgo/cookies"
+	"github.com/Beesonn/dlkitgo/instagram/providers"
+)
+
+var instagramURL = &url.URL{Scheme: "https", Host: "www.instagram.com", Path: "/"}
+
+// LoadCookies logs in with the Instagram cookies in a Netscape cookies.txt
+// file. Cookies Instagram refreshes are written back to the file.
+func (insta *InstaService) LoadCookies(path string) error {
+	jar, err := cookies.Load(path)
+	if err != nil {
+		return err
+	}
+	if jar.Value(instagramURL, "sessionid") == "" {
+		return fmt.Errorf("%s has no Instagram sessionid cookie", path)
+	}
+	insta.UseSession(jar)
+	return nil
+}
+
+// SetSessionID logs in with the value of the sessionid cookie of a browser
+// that is logged in to Instagram.
+func (insta *InstaService) SetSessionID(sessionID string) error {
+	sessionID = strings.TrimSpace(sessionID)
+	if sessionID == "" {
+		return errors.New("sessionid cannot be empty")
+	}
+	// The user ID is the part before the first %3A (an escaped colon).
+	userID, _, _ := strings.Cut(sessionID, "%3A")
+
+	jar := cookies.New("")
+	jar.Add(
+		&http.Cookie{Name: "sessionid", Value: sessionID, Domain: ".instagram.com", Path: "/", Secure: true, HttpOnly: true},
+		&http.Cookie{Name: "ds_user_id", Value: userID, Domain: ".instagram.com", Path: "/", Secure: true},
+	)
+	insta.UseSession(jar)
+	return nil
+}
+
+// UseSession sends every Instagram request, including those of the Web
+// provider, with the cookies in jar. The service gets its own copy of the
+// HTTP client so other services never see the jar.
+func (insta *InstaService) UseSession(jar *cookies.Jar) {
+	client := cookies.Client(insta.Client, jar)
+	insta.Client = client
+	insta.Session = jar
+	for _, provider := range insta.Providers {
+		if p, ok := provider.(*providers.Web); ok {
+			p.Client = client
+		}
+	}
+}
+
+// ValidateSession checks that the session is logged in and returns the
+// username it belongs to.
+func (insta *InstaService) ValidateSession(ctx context.Context) (string, error) {
+	if insta.Session == nil {
+		return "", errors.New("no Instagram session loaded")
+	}
+
+	var res struct {
+		User struct {
+			Username string `json:"username"`
+		} `json:"user"`
+	}
+	if err := insta.APIGet(ctx, "/api/v1/accounts/current_user/", url.Values{"edit": {"true"}}, &res); err != nil {
+		return "", fmt.Errorf("could not validate Instagram session: %w", err)
+	}
+	if res.User.Username == "" {
+		return "", errors.New("Instagram session is not logged in")
+	}
+	return res.User.Username, nil
+}
@@ -0,0 +1,293 @@
+package instagram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Beesonn/dlkitgo/capability"
+	"github.com/Beesonn/dlkitgo/errkind"
+	"github.com/Beesonn/dlkitgo/instagram/providers"
+)
+
+type HighlightResult struct {
+	Highlight
+	providers.InstaStreamResult
+}
+
+var HighlightIDRegex = regexp.MustCompile(`^(?:highlight:)?(\d+)$|instagram\.com/stories/highlights/(\d+)`)
+
+type apiReelsMedia struct {
+	Reels map[string]struct {
+		User struct {
+			Username string `json:"username"`
+		} `json:"user"`
+		Items []apiStoryItem `json:"items"`
+	} `json:"reels"`
+}
+
+type apiStoryItem struct {
+	TakenAt        int64 `json:"taken_at"`
+	ExpiringAt     int64 `json:"expiring_at"`
+	MediaType      int   `json:"media_type"`
+	ImageVersions2 struct {
+		Candidates []apiImageVersion `json:"candidates"`
+	} `json:"image_versions2"`
+	VideoVersions []apiImageVersion `json:"video_versions"`
+}
+
+type apiImageVersion struct {
+	URL string `json:"url"`
+}
+
+// StoryURL returns the link to the current stories of username.
+func StoryURL(username string) string {
+	return fmt.Sprintf("%s/stories/%s/", WebBaseURL, username)
+}
+
+// HighlightURL returns the link to a highlight reel.
+func HighlightURL(id string) string {
+	return fmt.Sprintf("%s/stories/highlights/%s/", WebBaseURL, id)
+}
+
+// HighlightID returns the numeric ID of a highlight from its URL or from
+// an ID with or without the "highlight:" prefix.
+func HighlightID(s string) (string, error) {
+	if m := HighlightIDRegex.FindStringSubmatch(strings.TrimSpace(s)); m != nil {
+		if m[1] != "" {
+			return m[1], nil
+		}
+		return m[2], nil
+	}
+	return "", errkind.New(errkind.ErrInvalidURL, "invalid Instagram highlight ID")
+}
+
+// Stories returns the current stories of username. Only providers that
+// support stories are asked. Timestamps and expiry come from Instagram
+// itself when it answers, and its media is used when every provider fails.
+func (i *InstaService) Stories(ctx context.Context, username string) (providers.InstaStreamResult, error) {
+	name, err := Username(username)
+	if err != nil {
+		return providers.InstaStreamResult{}, err
+	}
+
+	reelID := func(ctx context.Context) (string, error) {
+		profile, err := i.Profile(ctx, name)
+		if err != nil {
+			return "", err
+		}
+		return profile.ID, nil
+	}
+	res, err := i.streamReel(ctx, capability.Story, StoryURL(name), reelID)
+	if res.Username == "" {
+		res.Username = name
+	}
+	return res, err
+}
+
+// Highlight returns the media of a single highlight reel. id may be the
+// numeric ID or the highlight URL.
+func (i *InstaService) Highlight(ctx context.Context, id string) (providers.InstaStreamResult, error) {
+	hid, err := HighlightID(id)
+	if err != nil {
+		return providers.InstaStreamResult{}, err
+	}
+
+	reelID := func(context.Context) (string, error) {
+		return "highlight:" + hid, nil
+	}
+	return i.streamReel(ctx, capability.Highlight, HighlightURL(hid), reelID)
+}
+
+// Highlights yields every highlight reel of username together with its
+// media. A highlight that cannot be streamed is yielded with its error and
+// the iteration goes on.
+func (i *InstaService) Highlights(ctx context.Context, username string) iter.Seq2[HighlightResult, error] {
+	return func(yield func(HighlightResult, error) bool) {
+		profile, err := i.Profile(ctx, username)
+		if err != nil {
+			yield(HighlightResult{}, err)
+			return
+		}
+
+		for h, err := range profile.IterHighlights(ctx) {
+			if err != nil {
+				yield(HighlightResult{}, err)
+				return
+			}
+			res, err := i.Highlight(ctx, h.ID)
+			if res.Username == "" {
+				res.Username = profile.Username
+			}
+			if !yield(HighlightResult{Highlight: h, InstaStreamResult: res}, err) {
+				return
+			}
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}
+}
+
+// streamReel asks the providers that support kind for link, then adds the
+// timestamps of the reel reelID resolves to.
+func (i *InstaService) streamReel(ctx context.Context, kind, link string, reelID func(context.Context) (string, error)) (providers.InstaStreamResult, error) {
+	list := i.ProvidersFor(kind)
+
+	var res providers.InstaStreamResult
+	var streamed bool
+	for _, provider := range list {
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+		r, err := provider.Stream(link)
+		if err == nil && len(r.Source) > 0 {
+			res, streamed = r, true
+			break
+		}
+		if err == nil {
+			err = errors.New("no media returned")
+		}
+		fmt.Fprintf(os.Stderr, "Provider '%s' failed to stream: %v\n", provider.Name(), err)
+	}
+
+	// Instagram only answers with a login for most accounts, so this is
+	// best effort once a provider has streamed the reel.
+	username, items, apiErr := i.reelItems(ctx, reelID)
+	if res.Username == "" {
+		res.Username = username
+	}
+
+	if !streamed {
+		if apiErr != nil || len(items) == 0 {
+			err := errkind.New(errkind.ErrProvidersFailed, "all configured providers failed to stream the content")
+			if len(list) == 0 {
+				err = errkind.New(errkind.ErrProvidersFailed, "no configured provider supports %s links", kind)
+			}
+			// Whether the reel needs a session or is gone only shows in
+			// the API's answer.
+			if apiErr != nil {
+				err = fmt.Errorf("%w: %w", err, apiErr)
+			}
+			return res, err
+		}
+		res.Source = nil
+		for _, item := range items {
+			res.Source = append(res.Source, item.source(len(res.Source)))
+		}
+	} else {
+		addStoryTimes(res.Source, items)
+	}
+
+	res.Video, res.Photo = 0, 0
+	for _, src := range res.Source {
+		if src.Type == "video" {
+			res.Video++
+		} else {
+			res.Photo++
+		}
+	}
+	res.Total = res.Video + res.Photo
+	return res, nil
+}
+
+func (i *InstaService) reelItems(ctx context.Context, reelID func(context.Context) (string, error)) (string, []apiStoryItem, error) {
+	id, err := reelID(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query := url.Values{}
+	query.Set("reel_ids", id)
+
+	var res apiReelsMedia
+	if err := i.APIGet(ctx, "/api/v1/feed/reels_media/", query, &res); err != nil {
+		return "", nil, err
+	}
+	reel, ok := res.Reels[id]
+	if !ok {
+		return "", nil, errkind.New(errkind.ErrNotFound, "reel %s not found", id)
+	}
+	return reel.User.Username, reel.Items, nil
+}
+
+func (item apiStoryItem) source(index int) providers.MediaSource {
+	src := providers.MediaSource{
+		Type:      "photo",
+		Index:     index,
+		TakenAt:   unixTime(item.TakenAt),
+		ExpiresAt: unixTime(item.ExpiringAt),
+	}
+	if len(item.ImageVersions2.Candidates) > 0 {
+		src.URL = item.ImageVersions2.Candidates[0].URL
+		src.Thumbnail = src.URL
+	}
+	if item.MediaType == 2 && len(item.VideoVersions) > 0 {
+		src.Type = "video"
+		src.URL = item.VideoVersions[0].URL
+	}
+	return src
+}
+
+// addStoryTimes copies timestamps onto provider sources. Providers list
+// their media in their own order, so sources are matched to items by the
+// CDN file name they share, which also works through the providers' own
+// download links.
+func addStoryTimes(sources []providers.MediaSource, items []apiStoryItem) {
+	byFile := map[string]apiStoryItem{}
+	for _, item := range items {
+		for _, v := range item.VideoVersions {
+			byFile[mediaFile(v.URL)] = item
+		}
+		for _, v := range item.ImageVersions2.Candidates {
+			byFile[mediaFile(v.URL)] = item
+		}
+	}
+	delete(byFile, "")
+
+	for n := range sources {
+		item, ok := byFile[mediaFile(sources[n].URL)]
+		if !ok {
+			continue
+		}
+		sources[n].TakenAt = unixTime(item.TakenAt)
+		sources[n].ExpiresAt = unixTime(item.ExpiringAt)
+	}
+}
+
+// mediaFile returns the file name of an Instagram CDN link, looking inside
+// the query of links that wrap one.
+func mediaFile(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	for _, values := range u.Query() {
+		for _, v := range values {
+			if strings.HasPrefix(v, "http") && v != raw {
+				if name := mediaFile(v); name != "" {
+					return name
+				}
+			}
+		}
+	}
+	name := path.Base(u.Path)
+	if !strings.Contains(name, "_n.") {
+		return ""
+	}
+	return name
+}
+
+func unixTime(sec int64) time.Time {
+	if sec <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0).UTC()
+}
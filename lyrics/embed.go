@@ -0,0 +1,52 @@
+package lyrics
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Beesonn/dlkitgo/lyrics/providers"
+	"github.com/Beesonn/dlkitgo/tag"
+)
+
+type EmbedOptions struct {
+	// Language is the ISO-639-2 code stored with the lyrics frames.
+	Language string
+	// Sidecar also writes the synced lyrics to an .lrc file next to the audio.
+	Sidecar bool
+}
+
+// Embed stores lyrics in a downloaded audio file. MP3 files get USLT and
+// SYLT frames, M4A files the plain lyrics in their ©lyr atom.
+func Embed(path string, lyr providers.Lyrics, opts EmbedOptions) error {
+	if lyr.Plain == "" && lyr.Synced == "" {
+		return errors.New("lyrics are empty")
+	}
+
+	var lrc LRC
+	var lrcErr error
+	if lyr.Synced != "" {
+		lrc, lrcErr = ParseLRC(lyr.Synced)
+	} else {
+		lrcErr = errors.New("no synced lyrics")
+	}
+
+	if opts.Sidecar && lrcErr == nil {
+		sidecar := strings.TrimSuffix(path, filepath.Ext(path)) + ".lrc"
+		if err := os.WriteFile(sidecar, []byte(lrc.String()), 0o644); err != nil {
+			return err
+		}
+	}
+
+	md := tag.Metadata{Lyrics: lyr.Plain, Language: opts.Language}
+	if md.Lyrics == "" {
+		md.Lyrics = lrc.Plain()
+	}
+	if lrcErr == nil {
+		for _, line := range lrc.Lines {
+			md.SyncedLyrics = append(md.SyncedLyrics, tag.SyncedLine{Time: line.Time, Text: line.Text})
+		}
+	}
+	return tag.Write(path, md)
+}
@@ -0,0 +1,133 @@
+package lyrics
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Line struct {
+	Time time.Duration `json:"time"`
+	Text string        `json:"text"`
+}
+
+type LRC struct {
+	Tags  map[string]string `json:"tags,omitempty"`
+	Lines []Line            `json:"lines"`
+}
+
+var (
+	LRCTimeRegex = regexp.MustCompile(`^\[(\d+):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+	LRCTagRegex  = regexp.MustCompile(`^\[([a-zA-Z#]+):(.*)\]$`)
+)
+
+// ParseLRC reads LRC text. Lines with several timestamps are expanded and the
+// [offset:] tag is applied, so the returned lines are sorted and final.
+func ParseLRC(text string) (LRC, error) {
+	lrc := LRC{Tags: map[string]string{}}
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var stamps []time.Duration
+		for {
+			m := LRCTimeRegex.FindStringSubmatch(line)
+			if m == nil {
+				break
+			}
+			stamps = append(stamps, parseLRCTime(m))
+			line = line[len(m[0]):]
+		}
+
+		if len(stamps) == 0 {
+			if m := LRCTagRegex.FindStringSubmatch(line); m != nil {
+				lrc.Tags[strings.ToLower(m[1])] = strings.TrimSpace(m[2])
+			}
+			continue
+		}
+
+		for _, stamp := range stamps {
+			lrc.Lines = append(lrc.Lines, Line{Time: stamp, Text: strings.TrimSpace(line)})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return lrc, err
+	}
+
+	if len(lrc.Lines) == 0 {
+		return lrc, errors.New("no timed lines found")
+	}
+
+	if offset, err := strconv.Atoi(lrc.Tags["offset"]); err == nil && offset != 0 {
+		// A positive offset shifts lyrics up, i.e. makes them appear sooner.
+		for i := range lrc.Lines {
+			lrc.Lines[i].Time -= time.Duration(offset) * time.Millisecond
+			if lrc.Lines[i].Time < 0 {
+				lrc.Lines[i].Time = 0
+			}
+		}
+		delete(lrc.Tags, "offset")
+	}
+
+	sort.SliceStable(lrc.Lines, func(i, j int) bool {
+		return lrc.Lines[i].Time < lrc.Lines[j].Time
+	})
+
+	return lrc, nil
+}
+
+func parseLRCTime(m []string) time.Duration {
+	minutes, _ := strconv.Atoi(m[1])
+	seconds, _ := strconv.Atoi(m[2])
+	d := time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+
+	if frac := m[3]; frac != "" {
+		n, _ := strconv.Atoi(frac)
+		for i := len(frac); i < 3; i++ {
+			n *= 10
+		}
+		d += time.Duration(n) * time.Millisecond
+	}
+	return d
+}
+
+// String writes the LRC back out, tags first, with centisecond timestamps.
+func (l LRC) String() string {
+	var b strings.Builder
+
+	keys := make([]string, 0, len(l.Tags))
+	for k := range l.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "[%s:%s]\n", k, l.Tags[k])
+	}
+
+	for _, line := range l.Lines {
+		fmt.Fprintf(&b, "[%s]%s\n", FormatLRCTime(line.Time), line.Text)
+	}
+	return b.String()
+}
+
+func FormatLRCTime(d time.Duration) string {
+	cs := d.Milliseconds() / 10
+	return fmt.Sprintf("%02d:%02d.%02d", cs/6000, cs/100%60, cs%100)
+}
+
+func (l LRC) Plain() string {
+	texts := make([]string, 0, len(l.Lines))
+	for _, line := range l.Lines {
+		texts = append(texts, line.Text)
+	}
+	return strings.Join(texts, "\n")
+}
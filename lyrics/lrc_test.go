@@ -0,0 +1,90 @@
+package lyrics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func ms(n int) time.Duration { return time.Duration(n) * time.Millisecond }
+
+func TestParseLRC(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		text     string
+		wantTags map[string]string
+		want     []Line
+		wantErr  bool
+	}{
+		{
+			name:     "tags and fractions",
+			text:     "[ar: Artist ]\n[ti:Title]\n[00:01.5]one\n[00:02.25] two \n[01:03.123]three",
+			wantTags: map[string]string{"ar": "Artist", "ti": "Title"},
+			want:     []Line{{ms(1500), "one"}, {ms(2250), "two"}, {ms(63123), "three"}},
+		},
+		{
+			name:     "repeated timestamps are expanded and sorted",
+			text:     "[00:10.00][00:01.00]chorus\n[00:05.00]verse",
+			wantTags: map[string]string{},
+			want:     []Line{{ms(1000), "chorus"}, {ms(5000), "verse"}, {ms(10000), "chorus"}},
+		},
+		{
+			name:     "offset shifts lines earlier and is consumed",
+			text:     "[offset:+500]\n[00:00.20]clamped\n[00:02.00]shifted",
+			wantTags: map[string]string{},
+			want:     []Line{{0, "clamped"}, {ms(1500), "shifted"}},
+		},
+		{
+			name:     "colon separated fraction",
+			text:     "[00:03:50]line",
+			wantTags: map[string]string{},
+			want:     []Line{{ms(3500), "line"}},
+		},
+		{
+			name:    "plain text",
+			text:    "no timestamps\nat all",
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			lrc, err := ParseLRC(tc.text)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("ParseLRC succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(lrc.Tags, tc.wantTags) {
+				t.Errorf("tags = %v, want %v", lrc.Tags, tc.wantTags)
+			}
+			if !reflect.DeepEqual(lrc.Lines, tc.want) {
+				t.Errorf("lines = %v, want %v", lrc.Lines, tc.want)
+			}
+		})
+	}
+}
+
+func TestLRCString(t *testing.T) {
+	lrc := LRC{
+		Tags:  map[string]string{"ti": "Title", "ar": "Artist"},
+		Lines: []Line{{ms(1230), "one"}, {ms(61000), "two"}},
+	}
+	want := "[ar:Artist]\n[ti:Title]\n[00:01.23]one\n[01:01.00]two\n"
+	if got := lrc.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	back, err := ParseLRC(lrc.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, lrc) {
+		t.Errorf("round trip = %v, want %v", back, lrc)
+	}
+	if got := back.Plain(); got != "one\ntwo" {
+		t.Errorf("Plain() = %q", got)
+	}
+}
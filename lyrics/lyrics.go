@@ -0,0 +1,107 @@
+package lyrics
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/Beesonn/dlkitgo/errkind"
+	"github.com/Beesonn/dlkitgo/lyrics/providers"
+	"github.com/Beesonn/dlkitgo/spotify"
+	"github.com/Beesonn/dlkitgo/youtube"
+)
+
+type LyricsService struct {
+	Client    *http.Client
+	Providers []LyricsProvider
+}
+
+var (
+	VideoNoiseRegex = regexp.MustCompile(`(?i)\s*[\(\[][^\)\]]*(official|lyric|video|audio|visuali[sz]er|hd|4k|mv)[^\)\]]*[\)\]]`)
+	FeaturingRegex  = regexp.MustCompile(`(?i)\s+(feat\.?|ft\.?|featuring)\s+.*$`)
+)
+
+func NewLyrics(client *http.Client) *LyricsService {
+	return &LyricsService{
+		Client:    client,
+		Providers: DefaultProviders(client),
+	}
+}
+
+// Get asks every provider in turn and prefers time-synced lyrics. Plain
+// lyrics are returned only if no provider has synced ones.
+func (l *LyricsService) Get(q providers.Query) (providers.Lyrics, error) {
+	if q.Title == "" {
+		return providers.Lyrics{}, errors.New("title cannot be empty")
+	}
+
+	var plain *providers.Lyrics
+	for _, provider := range l.Providers {
+		res, err := provider.Lyrics(q)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Provider '%s' failed to find lyrics: %v\n", provider.Name(), err)
+			continue
+		}
+		if res.Synced != "" || res.Instrumental {
+			if res.Plain == "" && res.Synced != "" {
+				if lrc, err := ParseLRC(res.Synced); err == nil {
+					res.Plain = lrc.Plain()
+				}
+			}
+			return res, nil
+		}
+		if plain == nil {
+			plain = &res
+		}
+	}
+
+	if plain != nil {
+		return *plain, nil
+	}
+	return providers.Lyrics{}, errkind.New(errkind.ErrProvidersFailed, "all configured providers failed to find lyrics")
+}
+
+func (l *LyricsService) ForTrack(track spotify.TrackInfo) (providers.Lyrics, error) {
+	return l.Get(QueryFromTrack(track))
+}
+
+func (l *LyricsService) ForVideo(video youtube.YouTubeData) (providers.Lyrics, error) {
+	return l.Get(QueryFromVideo(video))
+}
+
+func QueryFromTrack(track spotify.TrackInfo) providers.Query {
+	artist := track.Artist
+	if len(track.Artists) > 0 {
+		artist = track.Artists[0].Name
+	}
+	return providers.Query{
+		Title:    track.Name,
+		Artist:   artist,
+		Album:    track.Album,
+		Duration: track.Duration,
+	}
+}
+
+// QueryFromVideo guesses artist and title from video names such as
+// "Artist - Title (Official Video)".
+func QueryFromVideo(video youtube.YouTubeData) providers.Query {
+	name := VideoNoiseRegex.ReplaceAllString(video.Name, "")
+
+	q := providers.Query{Title: strings.TrimSpace(name)}
+	for _, sep := range []string{" - ", " – ", " — ", " | "} {
+		if parts := strings.SplitN(name, sep, 2); len(parts) == 2 {
+			q.Artist = strings.TrimSpace(parts[0])
+			q.Title = strings.TrimSpace(parts[1])
+			break
+		}
+	}
+	q.Title = strings.Trim(FeaturingRegex.ReplaceAllString(q.Title, ""), `"' `)
+
+	if len(video.Videos) > 0 {
+		q.Duration = video.Videos[0].Duration
+	}
+	return q
+}
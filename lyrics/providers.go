@@ -0,0 +1,20 @@
+package lyrics
+
+import (
+	"net/http"
+
+	"github.com/Beesonn/dlkitgo/lyrics/providers"
+)
+
+type LyricsProvider interface {
+	Name() string
+	BaseURL() string
+	Lyrics(q providers.Query) (providers.Lyrics, error)
+}
+
+func DefaultProviders(client *http.Client) []LyricsProvider {
+	return []LyricsProvider{
+		&providers.LRCLib{Client: client},
+		&providers.LyricsOvh{Client: client},
+	}
+}
@@ -0,0 +1,139 @@
+package providers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type LRCLib struct {
+	Client *http.Client
+}
+
+type lrcLibRecord struct {
+	TrackName    string  `json:"trackName"`
+	ArtistName   string  `json:"artistName"`
+	AlbumName    string  `json:"albumName"`
+	Duration     float64 `json:"duration"`
+	Instrumental bool    `json:"instrumental"`
+	PlainLyrics  string  `json:"plainLyrics"`
+	SyncedLyrics string  `json:"syncedLyrics"`
+}
+
+func (p *LRCLib) Name() string {
+	return "lrclib"
+}
+
+func (p *LRCLib) BaseURL() string {
+	return "https://lrclib.net"
+}
+
+func (p *LRCLib) Lyrics(q Query) (Lyrics, error) {
+	if q.Title == "" {
+		return Lyrics{}, errors.New("title cannot be empty")
+	}
+
+	record, err := p.Get(q)
+	if err != nil {
+		record, err = p.Search(q)
+		if err != nil {
+			return Lyrics{}, err
+		}
+	}
+
+	if record.PlainLyrics == "" && record.SyncedLyrics == "" && !record.Instrumental {
+		return Lyrics{}, errors.New("lyrics not found")
+	}
+
+	return Lyrics{
+		Title:        record.TrackName,
+		Artist:       record.ArtistName,
+		Plain:        record.PlainLyrics,
+		Synced:       record.SyncedLyrics,
+		Instrumental: record.Instrumental,
+		Source:       p.Name(),
+	}, nil
+}
+
+func (p *LRCLib) Get(q Query) (lrcLibRecord, error) {
+	params := url.Values{}
+	params.Set("track_name", q.Title)
+	params.Set("artist_name", q.Artist)
+	if q.Album != "" {
+		params.Set("album_name", q.Album)
+	}
+	if q.Duration > 0 {
+		params.Set("duration", strconv.Itoa(q.Duration))
+	}
+
+	var record lrcLibRecord
+	err := p.DoRequest("/api/get?"+params.Encode(), &record)
+	return record, err
+}
+
+func (p *LRCLib) Search(q Query) (lrcLibRecord, error) {
+	params := url.Values{}
+	params.Set("track_name", q.Title)
+	if q.Artist != "" {
+		params.Set("artist_name", q.Artist)
+	}
+
+	var records []lrcLibRecord
+	if err := p.DoRequest("/api/search?"+params.Encode(), &records); err != nil {
+		return lrcLibRecord{}, err
+	}
+	if len(records) == 0 {
+		return lrcLibRecord{}, errors.New("lyrics not found")
+	}
+
+	best := records[0]
+	if q.Duration > 0 {
+		bestDelta := -1.0
+		for _, r := range records {
+			delta := r.Duration - float64(q.Duration)
+			if delta < 0 {
+				delta = -delta
+			}
+			if bestDelta < 0 || delta < bestDelta {
+				best, bestDelta = r, delta
+			}
+		}
+	}
+	return best, nil
+}
+
+func (p *LRCLib) DoRequest(path string, v interface{}) error {
+	if p.Client == nil {
+		p.Client = &http.Client{}
+	}
+
+	req, err := http.NewRequest("GET", p.BaseURL()+path, nil)
+	if err != nil {
+		return fmt.Errorf("request error: %v", err)
+	}
+	req.Header.Set("User-Agent", "dlkitgo (https://github.com/Beesonn/dlkitgo)")
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("api error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read error: %v", err)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("json error: %v", err)
+	}
+	return nil
+}
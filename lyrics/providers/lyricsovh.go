@@ -0,0 +1,64 @@
+package providers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type LyricsOvh struct {
+	Client *http.Client
+}
+
+func (p *LyricsOvh) Name() string {
+	return "lyricsovh"
+}
+
+func (p *LyricsOvh) BaseURL() string {
+	return "https://api.lyrics.ovh"
+}
+
+func (p *LyricsOvh) Lyrics(q Query) (Lyrics, error) {
+	if q.Title == "" || q.Artist == "" {
+		return Lyrics{}, errors.New("title and artist are required")
+	}
+
+	if p.Client == nil {
+		p.Client = &http.Client{}
+	}
+
+	// Only the main artist is known to the API.
+	artist := strings.TrimSpace(strings.Split(q.Artist, ",")[0])
+	apiURL := fmt.Sprintf("%s/v1/%s/%s", p.BaseURL(), url.PathEscape(artist), url.PathEscape(q.Title))
+
+	resp, err := p.Client.Get(apiURL)
+	if err != nil {
+		return Lyrics{}, fmt.Errorf("api error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Lyrics{}, fmt.Errorf("HTTP %s", resp.Status)
+	}
+
+	var result struct {
+		Lyrics string `json:"lyrics"`
+		Error  string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return Lyrics{}, fmt.Errorf("json error: %v", err)
+	}
+	if result.Lyrics == "" {
+		return Lyrics{}, errors.New("lyrics not found")
+	}
+
+	return Lyrics{
+		Title:  q.Title,
+		Artist: artist,
+		Plain:  strings.TrimSpace(strings.ReplaceAll(result.Lyrics, "\r\n", "\n")),
+		Source: p.Name(),
+	}, nil
+}
@@ -0,0 +1,17 @@
+package providers
+
+type Query struct {
+	Title    string `json:"title"`
+	Artist   string `json:"artist"`
+	Album    string `json:"album,omitempty"`
+	Duration int    `json:"duration,omitempty"`
+}
+
+type Lyrics struct {
+	Title        string `json:"title"`
+	Artist       string `json:"artist"`
+	Plain        string `json:"plain"`
+	Synced       string `json:"synced,omitempty"`
+	Instrumental bool   `json:"instrumental,omitempty"`
+	Source       string `json:"source"`
+}
@@ -0,0 +1,113 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Beesonn/dlkitgo/naming"
+)
+
+var mimeExtensions = map[string]string{
+	"audio/mpeg":  "mp3",
+	"audio/mp4":   "m4a",
+	"audio/x-m4a": "m4a",
+	"audio/ogg":   "ogg",
+	"audio/webm":  "webm",
+	"video/mp4":   "mp4",
+	"video/webm":  "webm",
+	"image/jpeg":  "jpg",
+	"image/png":   "png",
+	"image/webp":  "webp",
+	"image/gif":   "gif",
+}
+
+// download saves mediaURL under the name the platform's template gives
+// fields. The extension comes from the response, falling back to ext.
+func (m *Manager) download(ctx context.Context, platform, mediaURL string, fields naming.Fields, ext string) (string, error) {
+	namer, ok := m.Namers[platform]
+	if !ok {
+		return "", fmt.Errorf("no output template for %s", platform)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", mediaURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+
+	client := m.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download returned status %d", resp.StatusCode)
+	}
+
+	if e := responseExtension(resp); e != "" {
+		ext = e
+	}
+
+	dest, err := namer.Path(fields.With("ext", ext))
+	if err != nil {
+		return "", err
+	}
+
+	if err := writeFile(dest, resp.Body); err != nil {
+		namer.Release(dest)
+		return "", err
+	}
+	return dest, nil
+}
+
+func responseExtension(resp *http.Response) string {
+	if mt, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil {
+		if ext, ok := mimeExtensions[mt]; ok {
+			return ext
+		}
+	}
+	ext := strings.TrimPrefix(path.Ext(resp.Request.URL.Path), ".")
+	for _, known := range mimeExtensions {
+		if strings.EqualFold(ext, known) {
+			return known
+		}
+	}
+	return ""
+}
+
+// writeFile streams r into a partial file next to dest and only renames it
+// into place once complete.
+func writeFile(dest string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+
+	part := dest + ".part"
+	f, err := os.Create(part)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(part)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(part)
+		return err
+	}
+	return os.Rename(part, dest)
+}
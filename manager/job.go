@@ -0,0 +1,49 @@
+package manager
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+type State string
+
+const (
+	Pending State = "pending"
+	Running State = "running"
+	Done    State = "done"
+	Failed  State = "failed"
+	// Skipped jobs were found in the download archive.
+	Skipped State = "skipped"
+)
+
+type Job struct {
+	ID       string `json:"id"`
+	URL      string `json:"url"`
+	Platform string `json:"platform"`
+	// ItemID is the canonical platform ID used by the download archive.
+	ItemID string `json:"item_id,omitempty"`
+	// Parent is the job of the playlist or album this job was expanded from.
+	Parent   string   `json:"parent,omitempty"`
+	State    State    `json:"state"`
+	Attempts int      `json:"attempts"`
+	Output   []string `json:"output,omitempty"`
+	// Items maps each saved file of a job that downloads several, such as
+	// a show or a carousel, to its path, so a retry only fetches the rest.
+	Items map[string]string `json:"items,omitempty"`
+	Error string            `json:"error,omitempty"`
+	// ErrorKind is dlkitgo.ErrorKind of the error, which Error alone loses.
+	ErrorKind string `json:"error_kind,omitempty"`
+	// NextAttempt is when a failed job may be retried.
+	NextAttempt time.Time `json:"next_attempt,omitzero"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// JobID derives a stable ID from the URL, so adding a link twice finds the
+// existing job.
+func JobID(url string) string {
+	sum := sha1.Sum([]byte(strings.TrimSpace(url)))
+	return hex.EncodeToString(sum[:8])
+}
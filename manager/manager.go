@@ -0,0 +1,368 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Beesonn/dlkitgo"
+	"github.com/Beesonn/dlkitgo/archive"
+	"github.com/Beesonn/dlkitgo/errkind"
+	"github.com/Beesonn/dlkitgo/instagram"
+	"github.com/Beesonn/dlkitgo/naming"
+	"github.com/Beesonn/dlkitgo/youtube"
+)
+
+const (
+	DefaultWorkers     = 4
+	DefaultMaxAttempts = 3
+	// DefaultRetryDelay is the wait before the first retry of a failed job.
+	// Every further retry waits twice as long, up to maxRetryDelay.
+	DefaultRetryDelay = 30 * time.Second
+	maxRetryDelay     = 30 * time.Minute
+)
+
+var DefaultTemplates = map[string]string{
+	"spotify":   "spotify/{album_artist|Unknown Artist}/{album|Singles}/{artist} - {title}.{ext}",
+	"youtube":   "youtube/{title} [{id}].{ext}",
+	"instagram": "instagram/{username|unknown}/{shortcode}_{index}.{ext}",
+	"pinterest": "pinterest/{id|pin} {title:.80}.{ext}",
+}
+
+// DefaultPlatformLimits keeps a batch from hammering a single downloader
+// site while the others sit idle.
+var DefaultPlatformLimits = map[string]int{
+	"spotify":   2,
+	"youtube":   2,
+	"instagram": 2,
+	"pinterest": 2,
+}
+
+type Manager struct {
+	Kit *dlkitgo.Dlkit
+	// Client downloads the media. It has no timeout of its own, downloads
+	// are bounded by the context passed to Run.
+	Client *http.Client
+	Store  *Store
+	Namers map[string]*naming.Namer
+	// Archive, when set, skips items downloaded before, even by other
+	// stores, and records every item this manager downloads.
+	Archive *archive.Archive
+
+	Workers        int
+	PlatformLimits map[string]int
+	MaxAttempts    int
+	RetryDelay     time.Duration
+	// Quality selects the YouTube and Pinterest source, see pickSource.
+	// The best available video is used when it is empty.
+	Quality string
+	// Tag writes Spotify metadata and cover art into downloaded audio.
+	Tag bool
+	// Playlist, one of PlaylistFormats, writes a playlist file below
+	// PlaylistDir for every downloaded album or playlist. Empty disables it.
+	Playlist    string
+	PlaylistDir string
+	// OnUpdate is called whenever a job changes state.
+	OnUpdate func(Job)
+}
+
+// NewManager creates a manager that keeps its jobs in storePath and
+// downloads below outputDir using DefaultTemplates.
+func NewManager(kit *dlkitgo.Dlkit, storePath, outputDir string) (*Manager, error) {
+	store, err := OpenStore(storePath)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manager{
+		Kit:            kit,
+		Client:         &http.Client{},
+		Store:          store,
+		Namers:         map[string]*naming.Namer{},
+		Workers:        DefaultWorkers,
+		PlatformLimits: DefaultPlatformLimits,
+		MaxAttempts:    DefaultMaxAttempts,
+		RetryDelay:     DefaultRetryDelay,
+		Tag:            true,
+		PlaylistDir:    outputDir,
+	}
+
+	for platform, pattern := range DefaultTemplates {
+		namer, err := naming.NewNamer(pattern, outputDir)
+		if err != nil {
+			return nil, err
+		}
+		m.Namers[platform] = namer
+	}
+
+	return m, nil
+}
+
+// Add queues urls. Links already in the store, finished or not, are kept
+// as they are.
+func (m *Manager) Add(urls ...string) ([]Job, error) {
+	var jobs []Job
+	for _, url := range urls {
+		url = strings.TrimSpace(url)
+		if url == "" {
+			continue
+		}
+		platform := dlkitgo.DetectPlatform(url)
+		if platform == "" {
+			return jobs, errkind.New(errkind.ErrInvalidURL, "unsupported URL: %s", url)
+		}
+		job, _, err := m.Store.Add(url, platform, "")
+		if err != nil {
+			return jobs, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, m.Store.Flush()
+}
+
+// Run processes queued jobs until none are left or ctx is cancelled. Jobs
+// added while running, such as the tracks of an expanded playlist, are
+// picked up as well, and failed jobs are retried once their backoff ends.
+// Playlist files are written once every job is settled.
+func (m *Manager) Run(ctx context.Context) error {
+	defer m.flush()
+
+	start := time.Now()
+	workers := m.Workers
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	global := make(chan struct{}, workers)
+
+	platforms := map[string]chan struct{}{}
+	for platform, limit := range m.PlatformLimits {
+		if limit > 0 {
+			platforms[platform] = make(chan struct{}, limit)
+		}
+	}
+
+	for {
+		jobs := m.Store.Runnable(m.maxAttempts(), time.Now())
+		if len(jobs) == 0 {
+			next := m.Store.NextRetry(m.maxAttempts())
+			if next.IsZero() {
+				m.writePlaylists(start)
+				return nil
+			}
+			// Only failed jobs waiting for their retry are left.
+			if !sleep(ctx, time.Until(next)) {
+				return ctx.Err()
+			}
+			continue
+		}
+
+		var wg sync.WaitGroup
+		for _, job := range jobs {
+			wg.Add(1)
+			go func(job Job) {
+				defer wg.Done()
+
+				if sem, ok := platforms[job.Platform]; ok {
+					if !acquire(ctx, sem) {
+						return
+					}
+					defer func() { <-sem }()
+				}
+				if !acquire(ctx, global) {
+					return
+				}
+				defer func() { <-global }()
+
+				m.runJob(ctx, job)
+			}(job)
+		}
+		wg.Wait()
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+func (m *Manager) runJob(ctx context.Context, job Job) {
+	if job.ItemID == "" {
+		job.ItemID = m.ItemID(job)
+	}
+	if m.Archive.Has(job.Platform, job.ItemID) {
+		job.State = Skipped
+		job.Error, job.ErrorKind = "", ""
+		m.update(job)
+		return
+	}
+
+	job.State = Running
+	job.Attempts++
+	job.Error, job.ErrorKind = "", ""
+	m.update(job)
+
+	output, err := m.process(ctx, &job)
+	if ctx.Err() != nil {
+		// Cancelled jobs are retried on the next run without using up an
+		// attempt.
+		job.State = Pending
+		job.Attempts--
+		m.update(job)
+		return
+	}
+
+	if err != nil {
+		job.State = Failed
+		job.Error = err.Error()
+		job.ErrorKind = dlkitgo.ErrorKind(err)
+		job.NextAttempt = time.Now().Add(m.retryDelay(job.Attempts))
+	} else {
+		job.State = Done
+		job.NextAttempt = time.Time{}
+		job.Output = output
+		job.Items = nil
+		m.archive(job.Platform, job.ItemID)
+	}
+	m.update(job)
+}
+
+// ItemID returns the canonical ID of the single item job downloads, or an
+// empty string for collections such as playlists.
+func (m *Manager) ItemID(job Job) string {
+	var id string
+	switch job.Platform {
+	case "spotify":
+		if ref, err := m.Kit.Spotify.ResolveRef(job.URL); err == nil && (ref.Type == "track" || ref.Type == "episode") {
+			id = ref.ID
+		}
+	case "youtube":
+		id, _ = youtube.VideoID(job.URL)
+	case "instagram":
+		id, _ = instagram.Shortcode(job.URL)
+	case "pinterest":
+		id, _ = m.Kit.Pinterest.PinID(job.URL)
+	}
+	return id
+}
+
+func (m *Manager) archive(platform, id string) {
+	if m.Archive == nil || id == "" {
+		return
+	}
+	if err := m.Archive.Add(platform, id); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to archive %s %s: %v\n", platform, id, err)
+	}
+}
+
+func (m *Manager) process(ctx context.Context, job *Job) ([]string, error) {
+	switch job.Platform {
+	case "spotify":
+		return m.processSpotify(ctx, job)
+	case "youtube":
+		return m.processYouTube(ctx, job)
+	case "instagram":
+		return m.processInstagram(ctx, job)
+	case "pinterest":
+		return m.processPinterest(ctx, job)
+	}
+	return nil, errors.New("unsupported platform: " + job.Platform)
+}
+
+func (m *Manager) update(job Job) {
+	save := m.Store.Update
+	if job.State == Running {
+		// A running job is queued again on restart anyway.
+		save = m.Store.Progress
+	}
+	if err := save(job); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save job %s: %v\n", job.ID, err)
+	}
+	if m.OnUpdate != nil {
+		m.OnUpdate(job)
+	}
+}
+
+func (m *Manager) flush() {
+	if err := m.Store.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save jobs: %v\n", err)
+	}
+}
+
+// savedItem returns the file an earlier attempt of job saved for key, if
+// it is still there.
+func savedItem(job *Job, key string) (string, bool) {
+	path, ok := job.Items[key]
+	if !ok {
+		return "", false
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// saveItem records that key of job was saved to path.
+func (m *Manager) saveItem(job *Job, key, path string) {
+	if job.Items == nil {
+		job.Items = map[string]string{}
+	}
+	job.Items[key] = path
+	if err := m.Store.Progress(*job); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save job %s: %v\n", job.ID, err)
+	}
+}
+
+// itemsError reports the items of a job that could not be saved, keeping
+// the first error for errors.Is.
+func itemsError(errs []error, total int) error {
+	switch {
+	case len(errs) == 0:
+		return nil
+	case total == 1:
+		return errs[0]
+	}
+	return fmt.Errorf("%d of %d items failed, first: %w", len(errs), total, errs[0])
+}
+
+func (m *Manager) maxAttempts() int {
+	if m.MaxAttempts <= 0 {
+		return DefaultMaxAttempts
+	}
+	return m.MaxAttempts
+}
+
+// retryDelay returns the backoff after a job's attempts-th failure.
+func (m *Manager) retryDelay(attempts int) time.Duration {
+	delay := m.RetryDelay
+	if delay <= 0 {
+		delay = DefaultRetryDelay
+	}
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func acquire(ctx context.Context, sem chan struct{}) bool {
+	select {
+	case sem <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,143 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Beesonn/dlkitgo/naming"
+	"github.com/Beesonn/dlkitgo/playlist"
+	"github.com/Beesonn/dlkitgo/spotify"
+)
+
+// PlaylistFormats are the values Manager.Playlist accepts.
+var PlaylistFormats = []string{"m3u", "m3u8", "xspf"}
+
+// writePlaylists writes a playlist file for every album or playlist with a
+// track that finished after since. Each file lists the downloaded tracks
+// in the collection's order and becomes the output of the collection's job.
+func (m *Manager) writePlaylists(since time.Time) {
+	if m.Playlist == "" {
+		return
+	}
+
+	parents := map[string]bool{}
+	for _, job := range m.Store.Jobs() {
+		if job.Parent != "" && job.State == Done && !job.UpdatedAt.Before(since) {
+			parents[job.Parent] = true
+		}
+	}
+
+	for id := range parents {
+		job, ok := m.Store.Get(id)
+		if !ok || job.Platform != "spotify" {
+			continue
+		}
+		path, err := m.writeSpotifyPlaylist(job)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write playlist for %s: %v\n", job.URL, err)
+			continue
+		}
+		if path != "" {
+			job.Output = []string{path}
+			m.update(job)
+		}
+	}
+}
+
+func (m *Manager) writeSpotifyPlaylist(job Job) (string, error) {
+	ref, err := m.Kit.Spotify.ResolveRef(job.URL)
+	if err != nil {
+		return "", err
+	}
+	if ref.Type != "album" && ref.Type != "playlist" {
+		return "", nil
+	}
+
+	info, err := m.Kit.Spotify.GetInfo(ref.URL())
+	if err != nil {
+		return "", err
+	}
+
+	// Tracks in the archive may have been downloaded by another job.
+	done := map[string]string{}
+	for _, j := range m.Store.Jobs() {
+		if j.Platform == "spotify" && j.State == Done && j.ItemID != "" && len(j.Output) > 0 {
+			done[j.ItemID] = j.Output[0]
+		}
+	}
+
+	// GetInfo may only hold the first tracks of a large collection, the
+	// full list comes from the same pages the job was expanded from.
+	info.Tracks = nil
+	var paths []string
+	for track, err := range m.Kit.Spotify.IterTracks(ref.URL()) {
+		if errors.Is(err, spotify.ErrTruncated) {
+			fmt.Fprintf(os.Stderr, "Playlist for %s may be incomplete: %v\n", job.URL, err)
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		info.Tracks = append(info.Tracks, track)
+
+		path := ""
+		if child, ok := m.Store.Get(JobID(track.URL)); ok && child.State == Done && len(child.Output) > 0 {
+			path = child.Output[0]
+		} else if m.Archive.Has("spotify", track.SpotifyID) {
+			path = m.archivedPath(track, done)
+		}
+		paths = append(paths, path)
+	}
+
+	pl := playlist.FromSpotify(info, paths)
+	if len(pl.Entries) == 0 {
+		return "", nil
+	}
+
+	name := naming.Sanitize(info.Name)
+	if name == "" {
+		name = ref.Type + " " + ref.ID
+	}
+	dest := filepath.Join(m.PlaylistDir, "spotify", name+"."+strings.ToLower(m.Playlist))
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return "", err
+	}
+	if err := playlist.Save(dest, pl); err != nil {
+		return "", err
+	}
+	return dest, nil
+}
+
+// audioExtensions are the extensions a downloaded track may have been
+// saved with, most likely first.
+var audioExtensions = []string{"mp3", "m4a", "ogg", "webm"}
+
+// archivedPath finds the file of a track skipped because it is in the
+// archive: the output of the job that downloaded it, or else the file its
+// name template gives it, if either is still there.
+func (m *Manager) archivedPath(track spotify.TrackInfo, done map[string]string) string {
+	if path, ok := done[track.SpotifyID]; ok {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+
+	namer, ok := m.Namers["spotify"]
+	if !ok {
+		return ""
+	}
+	for _, ext := range audioExtensions {
+		path, err := namer.Render(naming.FromTrack(track).With("ext", ext))
+		if err != nil {
+			return ""
+		}
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
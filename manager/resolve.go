@@ -0,0 +1,272 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Beesonn/dlkitgo/errkind"
+	"github.com/Beesonn/dlkitgo/instagram"
+	"github.com/Beesonn/dlkitgo/naming"
+	"github.com/Beesonn/dlkitgo/spotify"
+	"github.com/Beesonn/dlkitgo/tag"
+)
+
+// processSpotify downloads tracks, episodes and shows directly. Playlists,
+// albums and artists are expanded into one job per track, so a restart
+// resumes a large playlist track by track.
+func (m *Manager) processSpotify(ctx context.Context, job *Job) ([]string, error) {
+	ref, err := m.Kit.Spotify.ResolveRef(job.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	switch ref.Type {
+	case "playlist", "album", "artist":
+		for track, err := range m.Kit.Spotify.IterTracks(ref.URL()) {
+			if errors.Is(err, spotify.ErrTruncated) {
+				// The tracks that could be listed are queued regardless.
+				fmt.Fprintf(os.Stderr, "Queued only part of %s: %v\n", job.URL, err)
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if track.URL == "" || m.Archive.Has("spotify", track.SpotifyID) {
+				continue
+			}
+			if _, _, err := m.Store.Add(track.URL, "spotify", job.ID); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}
+
+	result, err := m.Kit.Spotify.Stream(ref.URL())
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Source) == 0 {
+		if len(result.Failed) > 0 {
+			return nil, errkind.New(errkind.ErrProvidersFailed, "%s", result.Failed[0].Error)
+		}
+		return nil, errkind.New(errkind.ErrProvidersFailed, "no stream sources available")
+	}
+
+	var output []string
+	var errs []error
+	for _, src := range result.Source {
+		// Shows download every episode in one job, so each one is checked.
+		if m.Archive.Has("spotify", src.SpotifyID) {
+			continue
+		}
+		if path, ok := savedItem(job, src.SpotifyID); ok {
+			output = append(output, path)
+			continue
+		}
+		path, err := m.download(ctx, job.Platform, src.URL, naming.FromTrackSource(src), "mp3")
+		if err != nil {
+			if ctx.Err() != nil {
+				return output, err
+			}
+			errs = append(errs, err)
+			continue
+		}
+		output = append(output, path)
+		m.saveItem(job, src.SpotifyID, path)
+
+		if m.Tag && m.Kit.Tag != nil {
+			// Tagging is best effort, the audio itself is already saved.
+			if err := m.Kit.Tag.Write(path, tag.FromTrackSource(src)); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to tag %s: %v\n", path, err)
+			}
+		}
+		m.archive("spotify", src.SpotifyID)
+	}
+	return output, itemsError(errs, len(result.Source))
+}
+
+func (m *Manager) processYouTube(ctx context.Context, job *Job) ([]string, error) {
+	res, err := m.Kit.Youtube.Stream(job.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]candidate, len(res.Source))
+	for i, src := range res.Source {
+		candidates[i] = candidate{Type: src.Type, Quality: src.Quality, URL: src.URL}
+	}
+	best := pickSource(candidates, m.Quality, "video")
+	if best < 0 {
+		return nil, errors.New("no source matches the requested quality")
+	}
+
+	ext := "mp4"
+	if res.Source[best].Type == "audio" {
+		ext = "mp3"
+	}
+
+	fields := naming.Fields{
+		"platform": "youtube",
+		"id":       res.ID,
+		"title":    res.Caption,
+		"duration": res.Duration,
+		"quality":  res.Source[best].Quality,
+		"url":      job.URL,
+	}
+	path, err := m.download(ctx, job.Platform, res.Source[best].URL, fields, ext)
+	if err != nil {
+		return nil, err
+	}
+	return []string{path}, nil
+}
+
+func (m *Manager) processInstagram(ctx context.Context, job *Job) ([]string, error) {
+	res, err := m.Kit.Instagram.Stream(job.URL)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Source) == 0 {
+		return nil, errkind.New(errkind.ErrProvidersFailed, "no stream sources available")
+	}
+
+	fields := naming.FromInstagram(job.URL, instagram.InstagramData{
+		Shortcode: res.Shortcode,
+		Username:  res.Username,
+		Caption:   res.Caption,
+	})
+
+	var output []string
+	var errs []error
+	for i, src := range res.Source {
+		key := strconv.Itoa(i + 1)
+		if path, ok := savedItem(job, key); ok {
+			output = append(output, path)
+			continue
+		}
+
+		ext := "jpg"
+		if src.Type == "video" {
+			ext = "mp4"
+		}
+		path, err := m.download(ctx, job.Platform, src.URL, fields.With("index", i+1), ext)
+		if err != nil {
+			if ctx.Err() != nil {
+				return output, err
+			}
+			errs = append(errs, err)
+			continue
+		}
+		output = append(output, path)
+		m.saveItem(job, key, path)
+	}
+	return output, itemsError(errs, len(res.Source))
+}
+
+func (m *Manager) processPinterest(ctx context.Context, job *Job) ([]string, error) {
+	res, err := m.Kit.Pinterest.Stream(job.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]candidate, len(res.Source))
+	for i, src := range res.Source {
+		candidates[i] = candidate{Type: src.Type, Quality: src.Quality, URL: src.URL}
+	}
+	// Video pins also list their cover image, the video is what is wanted.
+	best := pickSource(candidates, m.Quality, "video")
+	if best < 0 {
+		return nil, errors.New("no source matches the requested quality")
+	}
+
+	ext := "jpg"
+	if res.Source[best].Type == "video" {
+		ext = "mp4"
+	}
+	path, err := m.download(ctx, job.Platform, res.Source[best].URL, naming.FromPin(job.URL, res), ext)
+	if err != nil {
+		return nil, err
+	}
+	return []string{path}, nil
+}
+
+type candidate struct {
+	Type    string
+	Quality string
+	URL     string
+}
+
+// pickSource returns the index of the source matching quality, which is
+// either an exact label such as "720p", "audio", "video" or "best". Without
+// an exact match the highest quality source of the wanted type wins, and
+// any type is accepted when none of that type exist.
+func pickSource(sources []candidate, quality, defaultType string) int {
+	want := strings.ToLower(strings.TrimSpace(quality))
+
+	switch want {
+	case "", "best", "audio", "video":
+	default:
+		for i, src := range sources {
+			if src.URL != "" && strings.EqualFold(src.Quality, want) {
+				return i
+			}
+		}
+	}
+
+	typ := defaultType
+	switch {
+	case want == "audio", strings.HasSuffix(want, "kbps"):
+		typ = "audio"
+	case want == "video":
+		typ = "video"
+	}
+
+	best, bestQuality := -1, -1
+	for i, src := range sources {
+		if src.URL == "" || src.Type != typ {
+			continue
+		}
+		if q := qualityValue(src.Quality); q > bestQuality {
+			best, bestQuality = i, q
+		}
+	}
+	if best >= 0 || want == "audio" || want == "video" {
+		return best
+	}
+
+	for i, src := range sources {
+		if src.URL == "" {
+			continue
+		}
+		if q := qualityValue(src.Quality); q > bestQuality {
+			best, bestQuality = i, q
+		}
+	}
+	return best
+}
+
+// qualityValue orders labels such as "720p", "4K" or "320kbps".
+func qualityValue(label string) int {
+	label = strings.ToLower(strings.TrimSpace(label))
+	switch label {
+	case "4k":
+		return 2160
+	case "8k":
+		return 4320
+	}
+	end := strings.IndexFunc(label, func(r rune) bool { return r < '0' || r > '9' })
+	if end < 0 {
+		end = len(label)
+	}
+	n, err := strconv.Atoi(label[:end])
+	if err != nil {
+		return 0
+	}
+	return n
+}
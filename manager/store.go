@@ -0,0 +1,232 @@
+package manager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Store keeps every job in a JSON file that is rewritten atomically, so a
+// crash never leaves it half written. Settled jobs are written at once,
+// while new jobs and progress are batched into one write per saveDelay.
+type Store struct {
+	Path string
+
+	mu    sync.Mutex
+	jobs  []*Job
+	index map[string]*Job
+	// timer is set while a batched write is scheduled.
+	timer *time.Timer
+}
+
+// saveDelay is how long new jobs and progress may stay unsaved. Expanding
+// a large playlist then costs a few writes rather than one per track.
+const saveDelay = 2 * time.Second
+
+// OpenStore loads the jobs saved at path. Jobs that were running when the
+// previous process stopped are queued again.
+func OpenStore(path string) (*Store, error) {
+	s := &Store{Path: path, index: map[string]*Job{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &s.jobs); err != nil {
+			return nil, err
+		}
+	}
+	for _, job := range s.jobs {
+		if job.State == Running {
+			job.State = Pending
+		}
+		s.index[job.ID] = job
+	}
+	return s, nil
+}
+
+// Add queues url unless a job for it exists, in which case that job is
+// returned with added set to false.
+func (s *Store) Add(url, platform, parent string) (job Job, added bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := JobID(url)
+	if existing, ok := s.index[id]; ok {
+		return *existing, false, nil
+	}
+
+	now := time.Now()
+	j := &Job{
+		ID:        id,
+		URL:       url,
+		Platform:  platform,
+		Parent:    parent,
+		State:     Pending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	s.jobs = append(s.jobs, j)
+	s.index[id] = j
+	s.saveLater()
+
+	return *j, true, nil
+}
+
+// Update replaces the stored job and writes the store at once.
+func (s *Store) Update(job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.set(job); err != nil {
+		return err
+	}
+	return s.save()
+}
+
+// Progress replaces the stored job like Update, but leaves the write to
+// the next batch.
+func (s *Store) Progress(job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.set(job); err != nil {
+		return err
+	}
+	s.saveLater()
+	return nil
+}
+
+// Flush writes any batched changes.
+func (s *Store) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.timer == nil {
+		return nil
+	}
+	return s.save()
+}
+
+func (s *Store) set(job Job) error {
+	existing, ok := s.index[job.ID]
+	if !ok {
+		return errors.New("job not found")
+	}
+	job.UpdatedAt = time.Now()
+	// The running job keeps changing its own map.
+	job.Items = maps.Clone(job.Items)
+	*existing = job
+	return nil
+}
+
+func (s *Store) saveLater() {
+	if s.Path == "" || s.timer != nil {
+		return
+	}
+	s.timer = time.AfterFunc(saveDelay, func() {
+		if err := s.Flush(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to save jobs to %s: %v\n", s.Path, err)
+		}
+	})
+}
+
+func (s *Store) Get(id string) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.index[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+func (s *Store) Jobs() []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]Job, len(s.jobs))
+	for i, job := range s.jobs {
+		jobs[i] = *job
+	}
+	return jobs
+}
+
+// Runnable lists pending jobs and failed ones with attempts left whose
+// retry is due at now, in the order they were added.
+func (s *Store) Runnable(maxAttempts int, now time.Time) []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var jobs []Job
+	for _, job := range s.jobs {
+		if job.State == Pending || (retryable(job, maxAttempts) && !job.NextAttempt.After(now)) {
+			jobs = append(jobs, *job)
+		}
+	}
+	return jobs
+}
+
+// NextRetry returns the earliest time a failed job with attempts left may
+// be retried, or the zero time if there is none.
+func (s *Store) NextRetry(maxAttempts int) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var next time.Time
+	for _, job := range s.jobs {
+		if retryable(job, maxAttempts) && (next.IsZero() || job.NextAttempt.Before(next)) {
+			next = job.NextAttempt
+		}
+	}
+	return next
+}
+
+func retryable(job *Job, maxAttempts int) bool {
+	return job.State == Failed && job.Attempts < maxAttempts
+}
+
+func (s *Store) save() error {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if s.Path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.jobs, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(s.Path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(s.Path)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.Path)
+}
@@ -0,0 +1,131 @@
+package naming
+
+import (
+	"github.com/Beesonn/dlkitgo/instagram"
+	"github.com/Beesonn/dlkitgo/pinterest"
+	"github.com/Beesonn/dlkitgo/spotify"
+	"github.com/Beesonn/dlkitgo/youtube"
+
+	pinproviders "github.com/Beesonn/dlkitgo/pinterest/providers"
+)
+
+// Fields is the unified metadata a template is rendered from. Values are
+// strings or ints; ints can be zero padded with {name:02}.
+type Fields map[string]any
+
+// With returns a copy of f with the given pairs added, typically the file
+// extension and the index of a media item.
+func (f Fields) With(pairs ...any) Fields {
+	out := make(Fields, len(f)+len(pairs)/2)
+	for k, v := range f {
+		out[k] = v
+	}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if key, ok := pairs[i].(string); ok {
+			out[key] = pairs[i+1]
+		}
+	}
+	return out
+}
+
+func FromTrack(track spotify.TrackInfo) Fields {
+	year := track.ReleaseDate
+	if len(year) > 4 {
+		year = year[:4]
+	}
+
+	return Fields{
+		"platform":     "spotify",
+		"id":           track.SpotifyID,
+		"title":        track.Name,
+		"artist":       track.Artist,
+		"album":        track.Album,
+		"album_artist": track.AlbumArtist,
+		"track":        track.TrackNumber,
+		"disc":         track.DiscNumber,
+		"date":         track.ReleaseDate,
+		"year":         year,
+		"isrc":         track.ISRC,
+		"duration":     track.Duration,
+		"url":          track.URL,
+	}
+}
+
+// FromTrackSource is FromTrack for a resolved stream, which is what is
+// actually downloaded.
+func FromTrackSource(src spotify.TrackSource) Fields {
+	return FromTrack(spotify.TrackInfo{
+		SpotifyID:   src.SpotifyID,
+		Name:        src.Title,
+		Artist:      src.Artist,
+		Image:       src.Image,
+		Duration:    src.Duration,
+		ReleaseDate: src.ReleaseDate,
+		Album:       src.Album,
+		AlbumArtist: src.AlbumArtist,
+		DiscNumber:  src.DiscNumber,
+		TrackNumber: src.TrackNumber,
+		ISRC:        src.ISRC,
+	})
+}
+
+// FromYouTube describes video as an entry of data, which may be the video
+// itself or the playlist it belongs to.
+func FromYouTube(data youtube.YouTubeData, video youtube.YouTubeVideoInfo) Fields {
+	f := Fields{
+		"platform": "youtube",
+		"id":       data.ID,
+		"title":    video.Name,
+		"duration": video.Duration,
+		"url":      video.URL,
+	}
+	if data.Type == "playlist" {
+		f["playlist"] = data.Name
+		f["playlist_id"] = data.ID
+		f["id"] = video.ID
+		if video.ID == "" {
+			f["id"], _ = youtube.VideoID(video.URL)
+		}
+	}
+	return f
+}
+
+// FromInstagram takes the post URL as well, for data without a shortcode.
+func FromInstagram(postURL string, data instagram.InstagramData) Fields {
+	shortcode := data.Shortcode
+	if shortcode == "" {
+		shortcode, _ = instagram.Shortcode(postURL)
+	}
+
+	var date, year string
+	if !data.TakenAt.IsZero() {
+		date = data.TakenAt.Format("2006-01-02")
+		year = date[:4]
+	}
+
+	return Fields{
+		"platform":  "instagram",
+		"id":        shortcode,
+		"shortcode": shortcode,
+		"username":  data.Username,
+		"title":     data.Caption,
+		"caption":   data.Caption,
+		"date":      date,
+		"year":      year,
+		"url":       postURL,
+	}
+}
+
+func FromPin(pinURL string, res pinproviders.PinResults) Fields {
+	id := res.ID
+	if m := pinterest.PinIDRegex.FindStringSubmatch(pinURL); id == "" && m != nil {
+		id = m[1]
+	}
+
+	return Fields{
+		"platform": "pinterest",
+		"id":       id,
+		"title":    res.Title,
+		"url":      pinURL,
+	}
+}
@@ -0,0 +1,106 @@
+package naming
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+type Collision int
+
+const (
+	// Rename appends " (1)", " (2)", ... before the extension.
+	Rename Collision = iota
+	Overwrite
+	Skip
+)
+
+var ErrExists = errors.New("file already exists")
+
+// Namer turns Fields into unique output paths under Root. Paths it hands
+// out are reserved until released, so concurrent downloads never pick the
+// same name before either file exists.
+type Namer struct {
+	Template  *Template
+	Root      string
+	Collision Collision
+
+	mu       sync.Mutex
+	reserved map[string]bool
+}
+
+func NewNamer(pattern, root string) (*Namer, error) {
+	t, err := Parse(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &Namer{Template: t, Root: root}, nil
+}
+
+// Path renders f and applies the collision policy. With Skip it returns
+// the taken path together with ErrExists.
+func (n *Namer) Path(f Fields) (string, error) {
+	path, err := n.Render(f)
+	if err != nil {
+		return "", err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.reserved == nil {
+		n.reserved = map[string]bool{}
+	}
+
+	if !n.taken(path) {
+		n.reserved[path] = true
+		return path, nil
+	}
+
+	switch n.Collision {
+	case Overwrite:
+		n.reserved[path] = true
+		return path, nil
+	case Skip:
+		return path, ErrExists
+	}
+
+	ext := filepath.Ext(path)
+	stem := strings.TrimSuffix(path, ext)
+	for i := 1; i < 10000; i++ {
+		candidate := fmt.Sprintf("%s (%d)%s", stem, i, ext)
+		if !n.taken(candidate) {
+			n.reserved[candidate] = true
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("no free name for %s", path)
+}
+
+// Render returns the path f renders to, before the collision policy
+// applies and without reserving it.
+func (n *Namer) Render(f Fields) (string, error) {
+	rel, err := n.Template.Execute(f)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(n.Root, filepath.FromSlash(rel)), nil
+}
+
+// Release forgets a reservation, for downloads that failed before
+// creating their file.
+func (n *Namer) Release(path string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.reserved, path)
+}
+
+func (n *Namer) taken(path string) bool {
+	if n.reserved[path] {
+		return true
+	}
+	_, err := os.Lstat(path)
+	return err == nil
+}
@@ -0,0 +1,88 @@
+package naming
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var reservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// Sanitize makes s safe to use inside a single file name on Linux, macOS
+// and Windows. Path separators are replaced too, so field values can never
+// introduce directories.
+func Sanitize(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		switch {
+		case r == '/' || r == '\\' || r == ':' || r == '|':
+			r = '-'
+		case r == '<' || r == '>' || r == '"' || r == '?' || r == '*':
+			continue
+		case unicode.IsControl(r) || unicode.IsSpace(r):
+			r = ' '
+		}
+
+		if r == ' ' {
+			if space {
+				continue
+			}
+			space = true
+		} else {
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// cleanComponent strips what Windows refuses at either end of a name and
+// keeps "." and ".." from escaping the output directory.
+func cleanComponent(c string) string {
+	c = strings.TrimSpace(c)
+	c = strings.TrimRight(c, ". ")
+	if c == "" {
+		return ""
+	}
+
+	base := c
+	if i := strings.IndexByte(base, '.'); i >= 0 {
+		base = base[:i]
+	}
+	if reservedNames[strings.ToUpper(base)] {
+		c = "_" + c
+	}
+	return c
+}
+
+// truncate cuts s to at most max bytes without splitting a UTF-8 sequence.
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	s = s[:max]
+	for len(s) > 0 && !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// truncateName is truncate for file names, keeping a short extension.
+func truncateName(name string, max int) string {
+	if len(name) <= max {
+		return name
+	}
+	ext := ""
+	if i := strings.LastIndexByte(name, '.'); i > 0 && len(name)-i <= 10 {
+		ext = name[i:]
+		name = name[:i]
+	}
+	return strings.TrimRight(truncate(name, max-len(ext)), ". ") + ext
+}
@@ -0,0 +1,58 @@
+package naming
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	for _, tc := range []struct {
+		in, want string
+	}{
+		{"Artist - Title", "Artist - Title"},
+		{"AC/DC", "AC-DC"},
+		{`a\b:c|d`, "a-b-c-d"},
+		{`What? <Live> "*"`, "What Live"},
+		{"  tabs\tand\nnew  lines  ", "tabs and new lines"},
+		{"../../etc", "..-..-etc"},
+		{"", ""},
+	} {
+		if got := Sanitize(tc.in); got != tc.want {
+			t.Errorf("Sanitize(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestCleanComponent(t *testing.T) {
+	for _, tc := range []struct {
+		in, want string
+	}{
+		{"name. ", "name"},
+		{"..", ""},
+		{"CON", "_CON"},
+		{"nul.txt", "_nul.txt"},
+		{"CONSOLE", "CONSOLE"},
+	} {
+		if got := cleanComponent(tc.in); got != tc.want {
+			t.Errorf("cleanComponent(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestTruncateName(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		max  int
+		want string
+	}{
+		{"short.mp3", 20, "short.mp3"},
+		{"a very long title.mp3", 10, "a very.mp3"},
+		// Multi-byte runes are never split.
+		{"ééééé.mp3", 8, "éé.mp3"},
+		{strings.Repeat("x", 12), 5, "xxxxx"},
+	} {
+		if got := truncateName(tc.in, tc.max); got != tc.want {
+			t.Errorf("truncateName(%q, %d) = %q, want %q", tc.in, tc.max, got, tc.want)
+		}
+	}
+}
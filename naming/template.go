@@ -0,0 +1,185 @@
+package naming
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxComponent keeps every path component under the 255 byte limit
+// of common filesystems, with room left for collision suffixes and
+// temporary files.
+const DefaultMaxComponent = 200
+
+type Template struct {
+	Pattern string
+	// MaxComponent is the maximum length in bytes of each directory or file
+	// name the template produces.
+	MaxComponent int
+	parts        []part
+}
+
+type part struct {
+	literal  string
+	field    string
+	width    int
+	pad      bool
+	maxLen   int
+	fallback string
+}
+
+// Parse compiles a pattern such as "{artist}/{album}/{track:02} - {title}.{ext}".
+// Fields accept a format after a colon: "02" zero pads numbers to two digits
+// and ".30" cuts text to 30 characters. "{album|Singles}" falls back to
+// "Singles" when album is empty. "{{" and "}}" are literal braces.
+func Parse(pattern string) (*Template, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return nil, errors.New("template cannot be empty")
+	}
+
+	t := &Template{Pattern: pattern, MaxComponent: DefaultMaxComponent}
+	var literal strings.Builder
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '{' && i+1 < len(pattern) && pattern[i+1] == '{':
+			literal.WriteByte('{')
+			i++
+		case c == '}' && i+1 < len(pattern) && pattern[i+1] == '}':
+			literal.WriteByte('}')
+			i++
+		case c == '}':
+			return nil, fmt.Errorf("unexpected } at offset %d", i)
+		case c == '{':
+			end := strings.IndexByte(pattern[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed { at offset %d", i)
+			}
+			p, err := parseField(pattern[i+1 : i+end])
+			if err != nil {
+				return nil, err
+			}
+			if literal.Len() > 0 {
+				t.parts = append(t.parts, part{literal: literal.String()})
+				literal.Reset()
+			}
+			t.parts = append(t.parts, p)
+			i += end
+		default:
+			literal.WriteByte(c)
+		}
+	}
+	if literal.Len() > 0 {
+		t.parts = append(t.parts, part{literal: literal.String()})
+	}
+
+	return t, nil
+}
+
+func MustParse(pattern string) *Template {
+	t, err := Parse(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func parseField(spec string) (part, error) {
+	var p part
+	if name, fallback, ok := strings.Cut(spec, "|"); ok {
+		spec, p.fallback = name, fallback
+	}
+
+	name, format, _ := strings.Cut(spec, ":")
+	p.field = strings.ToLower(strings.TrimSpace(name))
+	if p.field == "" {
+		return part{}, errors.New("empty field name in template")
+	}
+
+	switch {
+	case format == "":
+	case strings.HasPrefix(format, "."):
+		n, err := strconv.Atoi(format[1:])
+		if err != nil || n <= 0 {
+			return part{}, fmt.Errorf("invalid length %q for field %s", format, p.field)
+		}
+		p.maxLen = n
+	default:
+		p.pad = strings.HasPrefix(format, "0")
+		n, err := strconv.Atoi(format)
+		if err != nil || n <= 0 {
+			return part{}, fmt.Errorf("invalid width %q for field %s", format, p.field)
+		}
+		p.width = n
+	}
+
+	return p, nil
+}
+
+// Execute renders the template into a relative, filesystem safe path.
+// Directory components that render empty are dropped.
+func (t *Template) Execute(f Fields) (string, error) {
+	var out strings.Builder
+	for _, p := range t.parts {
+		if p.field == "" {
+			out.WriteString(p.literal)
+			continue
+		}
+		out.WriteString(Sanitize(p.render(f[p.field])))
+	}
+
+	max := t.MaxComponent
+	if max <= 0 {
+		max = DefaultMaxComponent
+	}
+
+	components := strings.Split(out.String(), "/")
+	name := cleanComponent(truncateName(cleanComponent(components[len(components)-1]), max))
+	if name == "" {
+		return "", errors.New("template rendered an empty file name")
+	}
+
+	result := make([]string, 0, len(components))
+	for _, c := range components[:len(components)-1] {
+		if c = cleanComponent(truncate(cleanComponent(c), max)); c != "" {
+			result = append(result, c)
+		}
+	}
+	return strings.Join(append(result, name), "/"), nil
+}
+
+func (p part) render(v any) string {
+	var s string
+	switch value := v.(type) {
+	case nil:
+	case string:
+		s = value
+	case int:
+		// Zero means unknown for track numbers, durations and the like.
+		if value != 0 {
+			s = strconv.Itoa(value)
+		}
+	default:
+		s = fmt.Sprint(value)
+	}
+
+	if s == "" {
+		return p.fallback
+	}
+
+	if p.width > 0 && len(s) < p.width {
+		fill := " "
+		if p.pad {
+			fill = "0"
+		}
+		s = strings.Repeat(fill, p.width-len(s)) + s
+	}
+	if p.maxLen > 0 {
+		if r := []rune(s); len(r) > p.maxLen {
+			s = strings.TrimSpace(string(r[:p.maxLen]))
+		}
+	}
+	return s
+}
@@ -0,0 +1,118 @@
+package naming
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTemplateExecute(t *testing.T) {
+	fields := Fields{
+		"artist": "AC/DC",
+		"album":  "",
+		"title":  "Highway to Hell",
+		"track":  3,
+		"disc":   0,
+		"year":   "1979",
+		"ext":    "mp3",
+	}
+
+	for _, tc := range []struct {
+		pattern string
+		want    string
+	}{
+		{"{artist} - {title}.{ext}", "AC-DC - Highway to Hell.mp3"},
+		{"{artist}/{album|Singles}/{track:02} {title}.{ext}", "AC-DC/Singles/03 Highway to Hell.mp3"},
+		{"{artist}/{album}/{title}.{ext}", "AC-DC/Highway to Hell.mp3"},
+		{"{title:.7}.{ext}", "Highway.mp3"},
+		{"{disc:02}-{track:02} {TITLE}.{ext}", "-03 Highway to Hell.mp3"},
+		{"{{{year}}} {title}.{ext}", "{1979} Highway to Hell.mp3"},
+		{"{missing|none}.{ext}", "none.mp3"},
+	} {
+		tmpl, err := Parse(tc.pattern)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tc.pattern, err)
+			continue
+		}
+		got, err := tmpl.Execute(fields)
+		if err != nil {
+			t.Errorf("%q: %v", tc.pattern, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%q rendered %q, want %q", tc.pattern, got, tc.want)
+		}
+	}
+}
+
+func TestTemplateErrors(t *testing.T) {
+	for _, pattern := range []string{"", "  ", "{title", "title}", "{}", "{track:x}", "{title:.0}"} {
+		if _, err := Parse(pattern); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", pattern)
+		}
+	}
+
+	if _, err := MustParse("{title}").Execute(Fields{}); err == nil {
+		t.Error("empty file name rendered without an error")
+	}
+}
+
+func TestNamerCollisions(t *testing.T) {
+	root := t.TempDir()
+	fields := Fields{"title": "song", "ext": "mp3"}
+
+	for _, tc := range []struct {
+		collision Collision
+		want      string
+		wantErr   error
+	}{
+		{Rename, "song (1).mp3", nil},
+		{Overwrite, "song.mp3", nil},
+		{Skip, "song.mp3", ErrExists},
+	} {
+		namer, err := NewNamer("{title}.{ext}", root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		namer.Collision = tc.collision
+
+		first, err := namer.Path(fields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(first, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := namer.Path(fields)
+		if err != tc.wantErr {
+			t.Errorf("collision %d: err = %v, want %v", tc.collision, err, tc.wantErr)
+		}
+		if want := filepath.Join(root, tc.want); got != want {
+			t.Errorf("collision %d: path = %q, want %q", tc.collision, got, want)
+		}
+		os.Remove(first)
+	}
+}
+
+func TestNamerReservesPaths(t *testing.T) {
+	namer, err := NewNamer("{title}.{ext}", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := Fields{"title": "song", "ext": "mp3"}
+
+	first, _ := namer.Path(fields)
+	second, _ := namer.Path(fields)
+	if first == second {
+		t.Fatalf("two reservations got the same path %q", first)
+	}
+
+	namer.Release(first)
+	if again, _ := namer.Path(fields); again != first {
+		t.Errorf("released path not reused: got %q, want %q", again, first)
+	}
+	if rendered, _ := namer.Render(fields); rendered != first {
+		t.Errorf("Render = %q, want %q", rendered, first)
+	}
+}
@@ -0,0 +1,45 @@
+package pinterest
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/Beesonn/dlkitgo/errkind"
+)
+
+var (
+	PinIDRegex     = regexp.MustCompile(`pinterest\.[a-z.]+/pin/(?:[^/?#]*--)?([0-9]+)`)
+	ShortLinkRegex = regexp.MustCompile(`^(?:https?://)?pin\.it/[a-zA-Z0-9]+`)
+)
+
+// PinID returns the numeric ID of a pin, following pin.it short links to
+// the pin they point at.
+func (p *PinService) PinID(url string) (string, error) {
+	if m := PinIDRegex.FindStringSubmatch(url); m != nil {
+		return m[1], nil
+	}
+	if !ShortLinkRegex.MatchString(url) {
+		return "", errkind.New(errkind.ErrInvalidURL, "no pin ID in Pinterest URL")
+	}
+
+	if !strings.HasPrefix(url, "http") {
+		url = "https://" + url
+	}
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	if m := PinIDRegex.FindStringSubmatch(resp.Request.URL.String()); m != nil {
+		return m[1], nil
+	}
+	return "", errkind.New(errkind.ErrInvalidURL, "short link does not point at a pin")
+}
@@ -4,7 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
 
+	"github.com/Beesonn/dlkitgo/cookies"
+	"github.com/Beesonn/dlkitgo/errkind"
 	"github.com/Beesonn/dlkitgo/pinterest/providers"
 )
 
@@ -20,17 +23,30 @@ func NewPin(client *http.Client) *PinService {
 	}
 }
 
+// UseCookies resolves pins on Pinterest with the cookies in jar.
+func (p *PinService) UseCookies(jar http.CookieJar) {
+	p.Client = cookies.Client(p.Client, jar)
+}
+
 func (p *PinService) Stream(url string) (providers.PinResults, error) {
 	if url == "" {
 		return providers.PinResults{}, errors.New("url cannot be empty")
 	}
 
-	for _, provider := range p.Providers {
+	kind := ContentKind(url)
+	list := p.ProvidersFor(kind)
+	if len(list) == 0 {
+		return providers.PinResults{}, errkind.New(errkind.ErrProvidersFailed, "no configured provider supports %s links", kind)
+	}
+	for _, provider := range list {
 		res, err := provider.Stream(url)
 		if err == nil {
+			if res.ID == "" {
+				res.ID, _ = p.PinID(url)
+			}
 			return res, nil
 		}
-		fmt.Printf("Provider '%s' failed to stream: %v\n", provider.Name(), err)
+		fmt.Fprintf(os.Stderr, "Provider '%s' failed to stream: %v\n", provider.Name(), err)
 	}
-	return providers.PinResults{}, errors.New("all configured providers failed to stream the content")
+	return providers.PinResults{}, errkind.New(errkind.ErrProvidersFailed, "all configured providers failed to stream the content")
 }
@@ -3,6 +3,7 @@ package pinterest
 import (
 	"net/http"
 
+	"github.com/Beesonn/dlkitgo/capability"
 	"github.com/Beesonn/dlkitgo/pinterest/providers"
 )
 
@@ -17,3 +18,17 @@ func DefaultProviders(client *http.Client) []Provider {
 		&providers.SavePin{Client: client},
 	}
 }
+
+// ContentKind returns the capability kind of a Pinterest link, or an
+// empty string if it is not one.
+func ContentKind(url string) string {
+	if PinIDRegex.MatchString(url) || ShortLinkRegex.MatchString(url) {
+		return capability.Pin
+	}
+	return ""
+}
+
+// ProvidersFor returns the configured providers that support kind.
+func (p *PinService) ProvidersFor(kind string) []Provider {
+	return capability.Filter(p.Providers, kind)
+}
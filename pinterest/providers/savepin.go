@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/Beesonn/dlkitgo/capability"
 	"github.com/PuerkitoBio/goquery"
 )
 
@@ -23,6 +24,12 @@ func (p *SavePin) BaseURL() string {
 	return "https://www.savepin.app"
 }
 
+func (p *SavePin) Capabilities() capability.Capabilities {
+	return capability.Capabilities{
+		Kinds: []string{capability.Pin},
+	}
+}
+
 func (p *SavePin) Stream(pinterestURL string) (PinResults, error) {
 	if pinterestURL == "" {
 		return PinResults{}, errors.New("pinterest URL cannot be empty")
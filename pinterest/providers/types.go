@@ -7,6 +7,7 @@ type PinSource struct {
 }
 
 type PinResults struct {
+	ID        string      `json:"id,omitempty"`
 	Title     string      `json:"title"`
 	Thumbnail string      `json:"thumbnail"`
 	Source    []PinSource `json:"source"`
@@ -0,0 +1,43 @@
+package playlist
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteM3U writes pl as an extended M3U playlist. The output is UTF-8, so it
+// serves for .m3u8 files as well.
+func WriteM3U(w io.Writer, pl Playlist) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "#EXTM3U")
+	if pl.Title != "" {
+		fmt.Fprintf(bw, "#PLAYLIST:%s\n", m3uText(pl.Title))
+	}
+
+	for _, e := range pl.Entries {
+		duration := e.Duration
+		if duration <= 0 {
+			duration = -1
+		}
+
+		title := e.Title
+		if e.Artist != "" {
+			title = e.Artist + " - " + e.Title
+		}
+
+		fmt.Fprintf(bw, "#EXTINF:%d,%s\n", duration, m3uText(title))
+		if e.Album != "" {
+			fmt.Fprintf(bw, "#EXTALB:%s\n", m3uText(e.Album))
+		}
+		fmt.Fprintln(bw, e.Path)
+	}
+
+	return bw.Flush()
+}
+
+func m3uText(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
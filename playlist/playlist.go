@@ -0,0 +1,112 @@
+package playlist
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Beesonn/dlkitgo/spotify"
+)
+
+type Entry struct {
+	// Path is the downloaded file. Entries without one are left out.
+	Path     string `json:"path"`
+	Title    string `json:"title"`
+	Artist   string `json:"artist,omitempty"`
+	Album    string `json:"album,omitempty"`
+	Duration int    `json:"duration,omitempty"`
+	Image    string `json:"image,omitempty"`
+	// Source is the page the entry was downloaded from.
+	Source string `json:"source,omitempty"`
+}
+
+type Playlist struct {
+	Title   string  `json:"title"`
+	Creator string  `json:"creator,omitempty"`
+	Image   string  `json:"image,omitempty"`
+	Source  string  `json:"source,omitempty"`
+	Entries []Entry `json:"entries"`
+}
+
+// FromSpotify builds a playlist from an album or playlist. paths[i] is the
+// file downloaded for data.Tracks[i]; missing or empty paths are skipped.
+func FromSpotify(data spotify.SpotifyData, paths []string) Playlist {
+	pl := Playlist{
+		Title:   data.Name,
+		Creator: data.Artist,
+		Image:   data.Image,
+		Source:  data.URL,
+	}
+	for i, track := range data.Tracks {
+		if i >= len(paths) || paths[i] == "" {
+			continue
+		}
+		pl.Entries = append(pl.Entries, Entry{
+			Path:     paths[i],
+			Title:    track.Name,
+			Artist:   track.Artist,
+			Album:    track.Album,
+			Duration: track.Duration,
+			Image:    track.Image,
+			Source:   track.URL,
+		})
+	}
+	return pl
+}
+
+// Save writes pl to path as M3U, M3U8 or XSPF depending on its extension.
+// Entry paths are written relative to the playlist's directory when they
+// share one, so the collection can be moved as a whole.
+func Save(path string, pl Playlist) error {
+	if len(pl.Entries) == 0 {
+		return errors.New("playlist has no entries")
+	}
+
+	dir := filepath.Dir(path)
+	rel := pl
+	rel.Entries = make([]Entry, len(pl.Entries))
+	for i, e := range pl.Entries {
+		e.Path = relativePath(dir, e.Path)
+		rel.Entries[i] = e
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".m3u", ".m3u8":
+		err = WriteM3U(f, rel)
+	case ".xspf":
+		err = WriteXSPF(f, rel)
+	default:
+		err = fmt.Errorf("unsupported playlist format: %s", filepath.Ext(path))
+	}
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+	}
+	return err
+}
+
+func relativePath(dir, path string) string {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return path
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(absDir, absPath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
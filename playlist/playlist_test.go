@@ -0,0 +1,127 @@
+package playlist
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Beesonn/dlkitgo/spotify"
+)
+
+var testPlaylist = Playlist{
+	Title:  "Road\nTrip",
+	Source: "https://open.spotify.com/playlist/abc",
+	Entries: []Entry{
+		{Path: "music/one.mp3", Title: "One", Artist: "Band", Album: "First", Duration: 181},
+		{Path: "music/two.mp3", Title: "Two"},
+	},
+}
+
+func TestWriteM3U(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteM3U(&buf, testPlaylist); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "#EXTM3U\n" +
+		"#PLAYLIST:Road Trip\n" +
+		"#EXTINF:181,Band - One\n" +
+		"#EXTALB:First\n" +
+		"music/one.mp3\n" +
+		"#EXTINF:-1,Two\n" +
+		"music/two.mp3\n"
+	if got := buf.String(); got != want {
+		t.Errorf("WriteM3U wrote\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteXSPF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteXSPF(&buf, testPlaylist); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc xspfPlaylist
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not valid XML: %v", err)
+	}
+	if len(doc.Tracks) != 2 {
+		t.Fatalf("got %d tracks, want 2", len(doc.Tracks))
+	}
+	if tr := doc.Tracks[0]; tr.Location != "music/one.mp3" || tr.Duration != 181000 || tr.Creator != "Band" {
+		t.Errorf("first track = %+v", tr)
+	}
+	if doc.Info != testPlaylist.Source {
+		t.Errorf("info = %q, want %q", doc.Info, testPlaylist.Source)
+	}
+}
+
+func TestXSPFLocation(t *testing.T) {
+	for _, tc := range []struct {
+		in, want string
+	}{
+		{"music/one song.mp3", "music/one%20song.mp3"},
+		{"/music/one.mp3", "file:///music/one.mp3"},
+		{"https://example.com/a.mp3", "https://example.com/a.mp3"},
+	} {
+		if got := xspfLocation(tc.in); got != tc.want {
+			t.Errorf("xspfLocation(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestFromSpotify(t *testing.T) {
+	data := spotify.SpotifyData{
+		Name:   "Album",
+		Artist: "Band",
+		Tracks: []spotify.TrackInfo{{Name: "One"}, {Name: "Two"}, {Name: "Three"}},
+	}
+
+	pl := FromSpotify(data, []string{"one.mp3", "", "three.mp3"})
+	if len(pl.Entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(pl.Entries))
+	}
+	if pl.Entries[0].Title != "One" || pl.Entries[1].Title != "Three" || pl.Entries[1].Path != "three.mp3" {
+		t.Errorf("entries = %+v", pl.Entries)
+	}
+	if pl.Title != "Album" || pl.Creator != "Band" {
+		t.Errorf("playlist = %q by %q", pl.Title, pl.Creator)
+	}
+}
+
+func TestSave(t *testing.T) {
+	dir := t.TempDir()
+	pl := Playlist{Entries: []Entry{
+		{Path: filepath.Join(dir, "music", "one.mp3"), Title: "One"},
+		{Path: "/elsewhere/two.mp3", Title: "Two"},
+	}}
+
+	path := filepath.Join(dir, "list.m3u8")
+	if err := Save(path, pl); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if got := lines[2]; got != "music/one.mp3" {
+		t.Errorf("path inside the playlist's directory = %q, want it relative", got)
+	}
+	if got := lines[4]; got != "/elsewhere/two.mp3" {
+		t.Errorf("path outside the playlist's directory = %q, want it unchanged", got)
+	}
+
+	if err := Save(filepath.Join(dir, "list.pls"), pl); err == nil {
+		t.Error("unsupported format saved without an error")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "list.pls")); err == nil {
+		t.Error("failed save left a file behind")
+	}
+	if err := Save(filepath.Join(dir, "empty.m3u"), Playlist{}); err == nil {
+		t.Error("empty playlist saved without an error")
+	}
+}
@@ -0,0 +1,83 @@
+package playlist
+
+import (
+	"encoding/xml"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+type xspfPlaylist struct {
+	XMLName xml.Name    `xml:"playlist"`
+	Version string      `xml:"version,attr"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	Title   string      `xml:"title,omitempty"`
+	Creator string      `xml:"creator,omitempty"`
+	Image   string      `xml:"image,omitempty"`
+	Info    string      `xml:"info,omitempty"`
+	Tracks  []xspfTrack `xml:"trackList>track"`
+}
+
+type xspfTrack struct {
+	Location string `xml:"location"`
+	Title    string `xml:"title,omitempty"`
+	Creator  string `xml:"creator,omitempty"`
+	Album    string `xml:"album,omitempty"`
+	Duration int    `xml:"duration,omitempty"`
+	Image    string `xml:"image,omitempty"`
+	Info     string `xml:"info,omitempty"`
+}
+
+// WriteXSPF writes pl as an XSPF playlist. Durations are stored in
+// milliseconds as the format requires.
+func WriteXSPF(w io.Writer, pl Playlist) error {
+	doc := xspfPlaylist{
+		Version: "1",
+		Xmlns:   "http://xspf.org/ns/0/",
+		Title:   pl.Title,
+		Creator: pl.Creator,
+		Image:   pl.Image,
+		Info:    pl.Source,
+	}
+
+	for _, e := range pl.Entries {
+		doc.Tracks = append(doc.Tracks, xspfTrack{
+			Location: xspfLocation(e.Path),
+			Title:    e.Title,
+			Creator:  e.Artist,
+			Album:    e.Album,
+			Duration: e.Duration * 1000,
+			Image:    e.Image,
+			Info:     e.Source,
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// xspfLocation turns a file path into the URI XSPF expects. Relative paths
+// stay relative references.
+func xspfLocation(path string) string {
+	if strings.Contains(path, "://") {
+		return path
+	}
+	slashed := filepath.ToSlash(path)
+	u := url.URL{Path: slashed}
+	if filepath.IsAbs(path) {
+		u.Scheme = "file"
+		if !strings.HasPrefix(slashed, "/") {
+			u.Path = "/" + slashed
+		}
+	}
+	return u.String()
+}
@@ -0,0 +1,123 @@
+package proxy
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultTTL = 6 * time.Hour
+
+// Proxy is an http.Handler that streams media from signed URLs. Links are
+// made with Sign or Wrap and carry the target, an expiry and an HMAC of
+// both, so the handler only ever fetches what this process handed out.
+type Proxy struct {
+	// BaseURL is where the handler is reachable, e.g.
+	// "https://api.example.com/v1/media".
+	BaseURL string
+	Key     []byte
+	TTL     time.Duration
+	// Client fetches the media, SafeClient when nil.
+	Client *http.Client
+}
+
+// New returns a proxy mounted at baseURL. A nil key is replaced by a random
+// one, which is fine as long as links are only served by this process.
+func New(baseURL string, key []byte) *Proxy {
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		rand.Read(key)
+	}
+	return &Proxy{BaseURL: baseURL, Key: key, TTL: DefaultTTL}
+}
+
+// Sign returns a proxied link to rawURL. A filename makes the media
+// download as an attachment with that name.
+func (p *Proxy) Sign(rawURL, filename string) string {
+	ttl := p.TTL
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	exp := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+
+	q := url.Values{}
+	q.Set("url", rawURL)
+	q.Set("exp", exp)
+	if filename != "" {
+		q.Set("name", filename)
+	}
+	q.Set("sig", p.signature(rawURL, exp, filename))
+
+	sep := "?"
+	if strings.Contains(p.BaseURL, "?") {
+		sep = "&"
+	}
+	return p.BaseURL + sep + q.Encode()
+}
+
+// Wrap is Sign without a file name. It fits the ProxyLink hook of the
+// Spotify providers.
+func (p *Proxy) Wrap(rawURL string) string {
+	return p.Sign(rawURL, "")
+}
+
+// Verify checks the signature and expiry of a proxied link's query and
+// returns its target and file name.
+func (p *Proxy) Verify(q url.Values) (*url.URL, string, error) {
+	rawURL, exp, name, sig := q.Get("url"), q.Get("exp"), q.Get("name"), q.Get("sig")
+	if rawURL == "" || exp == "" || sig == "" {
+		return nil, "", errors.New("link is not signed")
+	}
+
+	expected := p.signature(rawURL, exp, name)
+	if !hmac.Equal([]byte(sig), []byte(expected)) {
+		return nil, "", errors.New("invalid signature")
+	}
+
+	unix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil {
+		return nil, "", errors.New("invalid expiry")
+	}
+	if time.Now().Unix() > unix {
+		return nil, "", errors.New("link has expired")
+	}
+
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, "", err
+	}
+	return target, name, nil
+}
+
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "only GET and HEAD are supported")
+		return
+	}
+
+	target, name, err := p.Verify(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusForbidden, "forbidden", err.Error())
+		return
+	}
+
+	Forward(p.Client, w, r, target, name)
+}
+
+func (p *Proxy) signature(rawURL, exp, name string) string {
+	mac := hmac.New(sha256.New, p.Key)
+	mac.Write([]byte(rawURL))
+	mac.Write([]byte{0})
+	mac.Write([]byte(exp))
+	mac.Write([]byte{0})
+	mac.Write([]byte(name))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,210 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Beesonn/dlkitgo"
+	"github.com/Beesonn/dlkitgo/instagram"
+	"github.com/Beesonn/dlkitgo/spotify"
+	"github.com/Beesonn/dlkitgo/youtube"
+)
+
+type Response struct {
+	Platform string `json:"platform"`
+	Data     any    `json:"data"`
+}
+
+type Resolved struct {
+	Platform string `json:"platform"`
+	Type     string `json:"type"`
+	ID       string `json:"id"`
+	URL      string `json:"url"`
+}
+
+// target validates the url parameter and returns it with its platform.
+// Bare Spotify IDs are accepted when a type is given.
+func target(r *http.Request) (string, string, error) {
+	url := strings.TrimSpace(r.URL.Query().Get("url"))
+	if url == "" {
+		return "", "", invalid("url parameter is required")
+	}
+	if len(url) > 2048 {
+		return "", "", invalid("url parameter is too long")
+	}
+
+	platform := dlkitgo.DetectPlatform(url)
+	if platform == "" && spotify.SpotifyIDRegex.MatchString(url) {
+		platform = "spotify"
+	}
+	if platform == "" {
+		return "", "", invalid("unsupported URL: " + url)
+	}
+	return url, platform, nil
+}
+
+func (s *Server) handleInfo(w http.ResponseWriter, r *http.Request) {
+	url, platform, err := target(r)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	var data any
+	switch platform {
+	case "spotify":
+		data, err = s.Kit.Spotify.GetInfo(url, r.URL.Query().Get("type"))
+	case "youtube":
+		data, err = s.Kit.Youtube.GetInfo(url)
+	case "instagram":
+		data, err = s.Kit.Instagram.GetInfo(url)
+	case "pinterest":
+		// Pins have no separate metadata lookup, the stream carries it.
+		data, err = s.Kit.Pinterest.Stream(url)
+	}
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, Response{Platform: platform, Data: data})
+}
+
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	url, platform, err := target(r)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	kit, err := s.Kit.WithProvider(platform, r.URL.Query().Get("provider"))
+	if err != nil {
+		writeServiceError(w, invalid(err.Error()))
+		return
+	}
+
+	var data any
+	switch platform {
+	case "spotify":
+		data, err = kit.Spotify.Stream(url, r.URL.Query().Get("type"))
+	case "youtube":
+		data, err = kit.Youtube.Stream(url)
+	case "instagram":
+		data, err = kit.Instagram.Stream(url)
+	case "pinterest":
+		data, err = kit.Pinterest.Stream(url)
+	}
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, Response{Platform: platform, Data: data})
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	query := strings.TrimSpace(q.Get("q"))
+	if query == "" {
+		writeServiceError(w, invalid("q parameter is required"))
+		return
+	}
+
+	limit, err := intParam(q.Get("limit"), 20, 1, 50)
+	if err != nil {
+		writeServiceError(w, invalid("limit "+err.Error()))
+		return
+	}
+	offset, err := intParam(q.Get("offset"), 0, 0, 1000)
+	if err != nil {
+		writeServiceError(w, invalid("offset "+err.Error()))
+		return
+	}
+
+	platform := strings.ToLower(q.Get("platform"))
+	var data any
+	switch platform {
+	case "", "spotify":
+		platform = "spotify"
+		if _, err := s.Kit.Spotify.ParseSearchTypes(q.Get("type")); err != nil {
+			writeServiceError(w, invalid(err.Error()))
+			return
+		}
+		data, err = s.Kit.Spotify.SearchWithOptions(query, spotify.SearchOptions{
+			Type:   q.Get("type"),
+			Limit:  limit,
+			Offset: offset,
+			Market: q.Get("market"),
+		})
+	case "youtube":
+		data, err = s.Kit.Youtube.Search(query, limit)
+	default:
+		writeServiceError(w, invalid("platform must be spotify or youtube"))
+		return
+	}
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, Response{Platform: platform, Data: data})
+}
+
+// handleResolve normalizes a link to its canonical platform ID and URL,
+// following short links.
+func (s *Server) handleResolve(w http.ResponseWriter, r *http.Request) {
+	url, platform, err := target(r)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	res := Resolved{Platform: platform}
+	switch platform {
+	case "spotify":
+		var ref spotify.SpotifyRef
+		if ref, err = s.Kit.Spotify.ResolveRef(url, r.URL.Query().Get("type")); err == nil {
+			res.Type, res.ID, res.URL = ref.Type, ref.ID, ref.URL()
+		}
+	case "youtube":
+		if res.ID, err = youtube.VideoID(url); err == nil {
+			res.Type = "video"
+			if strings.Contains(url, "/shorts/") {
+				res.Type = "shorts"
+			}
+			res.URL = "https://www.youtube.com/watch?v=" + res.ID
+		}
+	case "instagram":
+		if res.ID, err = instagram.Shortcode(url); err == nil {
+			res.Type = "post"
+			res.URL = fmt.Sprintf("https://www.instagram.com/p/%s/", res.ID)
+		}
+	case "pinterest":
+		if res.ID, err = s.Kit.Pinterest.PinID(url); err == nil {
+			res.Type = "pin"
+			res.URL = fmt.Sprintf("https://www.pinterest.com/pin/%s/", res.ID)
+		}
+	}
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+func intParam(value string, def, min, max int) (int, error) {
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("must be a number")
+	}
+	if n < min || n > max {
+		return 0, fmt.Errorf("must be between %d and %d", min, max)
+	}
+	return n, nil
+}
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/Beesonn/dlkitgo/proxy"
+)
+
+func (s *Server) handleProxy(w http.ResponseWriter, r *http.Request) {
+	if !s.EnableProxy {
+		writeError(w, http.StatusNotFound, "not_found", "the media proxy is disabled")
+		return
+	}
+
+	raw := r.URL.Query().Get("url")
+	if raw == "" {
+		writeServiceError(w, invalid("url parameter is required"))
+		return
+	}
+	target, err := url.Parse(raw)
+	if err != nil {
+		writeServiceError(w, invalid(err.Error()))
+		return
+	}
+
+	proxy.Forward(nil, w, r, target, r.URL.Query().Get("filename"))
+}
+
+func (s *Server) handleMedia(w http.ResponseWriter, r *http.Request) {
+	if s.Media == nil {
+		writeError(w, http.StatusNotFound, "not_found", "signed media links are disabled")
+		return
+	}
+	s.Media.ServeHTTP(w, r)
+}
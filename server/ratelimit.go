@@ -0,0 +1,62 @@
+package server
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type limiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	calls   int
+}
+
+func newLimiter() *limiter {
+	return &limiter{buckets: map[string]*bucket{}}
+}
+
+// allow takes a token from key's bucket. It reports the tokens left and,
+// when refused, how long until the next one.
+func (l *limiter) allow(key string, limit Limit) (bool, int, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+
+	l.calls++
+	if l.calls%1024 == 0 {
+		l.prune(now, limit)
+	}
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+		return false, 0, wait
+	}
+	b.tokens--
+	return true, int(b.tokens), 0
+}
+
+// prune drops buckets that have been idle long enough to be full again,
+// so per-IP buckets do not pile up.
+func (l *limiter) prune(now time.Time, limit Limit) {
+	idle := time.Duration(float64(limit.Burst)/limit.Rate*float64(time.Second)) + time.Minute
+	for key, b := range l.buckets {
+		if now.Sub(b.last) > idle {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Beesonn/dlkitgo"
+	"github.com/Beesonn/dlkitgo/proxy"
+)
+
+// Limit is a token bucket: Rate requests per second on average, with bursts
+// of up to Burst requests.
+type Limit struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+var DefaultLimit = Limit{Rate: 1, Burst: 10}
+
+type Server struct {
+	Kit *dlkitgo.Dlkit
+
+	// APIKeys maps each accepted key to its rate limit. A zero Limit uses
+	// DefaultLimit. Without any keys the API is open and rate limited per
+	// client IP.
+	APIKeys      map[string]Limit
+	DefaultLimit Limit
+	// EnableProxy serves /v1/proxy, which streams media through the server.
+	EnableProxy bool
+	// Media serves the signed links it makes under /v1/media. Those need
+	// no API key, the signature is what authorizes them.
+	Media *proxy.Proxy
+
+	limiter *limiter
+	mux     *http.ServeMux
+}
+
+func New(kit *dlkitgo.Dlkit) *Server {
+	s := &Server{
+		Kit:          kit,
+		APIKeys:      map[string]Limit{},
+		DefaultLimit: DefaultLimit,
+		limiter:      newLimiter(),
+		mux:          http.NewServeMux(),
+	}
+
+	s.mux.HandleFunc("GET /v1/info", s.handleInfo)
+	s.mux.HandleFunc("GET /v1/stream", s.handleStream)
+	s.mux.HandleFunc("GET /v1/search", s.handleSearch)
+	s.mux.HandleFunc("GET /v1/resolve", s.handleResolve)
+	s.mux.HandleFunc("GET /v1/proxy", s.handleProxy)
+	s.mux.HandleFunc("GET /v1/media", s.handleMedia)
+	s.mux.HandleFunc("GET /openapi.json", s.handleOpenAPI)
+	s.mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "not_found", "no such endpoint")
+	})
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// The spec, health check and signed media stay reachable without a key.
+	if r.URL.Path == "/openapi.json" || r.URL.Path == "/healthz" || r.URL.Path == "/v1/media" {
+		s.mux.ServeHTTP(w, r)
+		return
+	}
+
+	key, limit, ok := s.authenticate(r)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="dlkit"`)
+		writeError(w, http.StatusUnauthorized, "unauthorized", "missing or invalid API key")
+		return
+	}
+
+	allowed, remaining, retry := s.limiter.allow(key, limit)
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit.Burst))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	if !allowed {
+		w.Header().Set("Retry-After", strconv.Itoa(int(retry/time.Second)+1))
+		writeError(w, http.StatusTooManyRequests, "rate_limited", "rate limit exceeded")
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// authenticate returns the rate limit bucket and limit for the request. Keys
+// are read from X-API-Key or an "Authorization: Bearer" header.
+func (s *Server) authenticate(r *http.Request) (string, Limit, bool) {
+	if len(s.APIKeys) == 0 {
+		return "ip:" + clientIP(r), s.defaultLimit(), true
+	}
+
+	key := r.Header.Get("X-API-Key")
+	if key == "" {
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			key = strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+		}
+	}
+	if key == "" {
+		return "", Limit{}, false
+	}
+
+	limit, ok := s.APIKeys[key]
+	if !ok {
+		return "", Limit{}, false
+	}
+	if limit.Rate <= 0 || limit.Burst <= 0 {
+		limit = s.defaultLimit()
+	}
+	return "key:" + key, limit, true
+}
+
+func (s *Server) defaultLimit() Limit {
+	if s.DefaultLimit.Rate <= 0 || s.DefaultLimit.Burst <= 0 {
+		return DefaultLimit
+	}
+	return s.DefaultLimit
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]apiError{"error": {Code: code, Message: message}})
+}
+
+// writeServiceError maps a service error onto an HTTP status using
+// dlkitgo.ErrorKind.
+func writeServiceError(w http.ResponseWriter, err error) {
+	var v *validationError
+	if errors.As(err, &v) {
+		writeError(w, http.StatusBadRequest, "invalid_request", v.Error())
+		return
+	}
+
+	switch kind := dlkitgo.ErrorKind(err); kind {
+	case dlkitgo.ErrKindInvalidURL:
+		writeError(w, http.StatusBadRequest, kind, err.Error())
+	case dlkitgo.ErrKindNotFound:
+		writeError(w, http.StatusNotFound, kind, err.Error())
+	case dlkitgo.ErrKindLoginRequired:
+		writeError(w, http.StatusForbidden, kind, err.Error())
+	case dlkitgo.ErrKindProvidersFailed:
+		writeError(w, http.StatusBadGateway, kind, err.Error())
+	default:
+		writeError(w, http.StatusBadGateway, "upstream_error", err.Error())
+	}
+}
+
+type validationError struct {
+	msg string
+}
+
+func (e *validationError) Error() string { return e.msg }
+
+func invalid(msg string) error {
+	return &validationError{msg: msg}
+}
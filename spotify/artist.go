@@ -0,0 +1,202 @@
+package spotify
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type ArtistRelease struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	URL         string `json:"url"`
+	Image       string `json:"image"`
+	ReleaseDate string `json:"release_date,omitempty"`
+	TotalTracks int    `json:"total_tracks,omitempty"`
+}
+
+var MonthlyListenersRegex = regexp.MustCompile(`([0-9][0-9.,]*)\s*([KkMmBb]?)\s+monthly listeners`)
+
+func (s *SpotifyService) ParseArtistOverview(entity map[string]interface{}, data *SpotifyData) {
+	if name, ok := lookupString(entity, "profile", "name"); ok && name != "" {
+		data.Name = name
+		data.Artist = name
+	}
+
+	if sources, ok := lookupSlice(entity, "visuals", "avatarImage", "sources"); ok {
+		if img := s.LargestImage(sources); img != "" {
+			data.Image = img
+		}
+	}
+
+	if listeners, ok := lookupFloat(entity, "stats", "monthlyListeners"); ok {
+		data.MonthlyListeners = int(listeners)
+	}
+	if followers, ok := lookupFloat(entity, "stats", "followers"); ok {
+		data.Followers = int(followers)
+	}
+
+	if items, ok := lookupSlice(entity, "discography", "topTracks", "items"); ok && len(data.Tracks) == 0 {
+		for _, item := range items {
+			itemMap, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if track, ok := itemMap["track"].(map[string]interface{}); ok {
+				s.AddArtistTopTrack(track, data)
+			}
+		}
+	}
+
+	data.Albums = s.ExtractArtistReleases(entity, "albums")
+	data.Singles = s.ExtractArtistReleases(entity, "singles")
+	data.Compilations = s.ExtractArtistReleases(entity, "compilations")
+}
+
+func (s *SpotifyService) AddArtistTopTrack(track map[string]interface{}, data *SpotifyData) {
+	name, _ := track["name"].(string)
+	uri, _ := track["uri"].(string)
+	parts := strings.Split(uri, ":")
+	if name == "" || len(parts) < 3 {
+		return
+	}
+
+	var artists []string
+	if items, ok := lookupSlice(track, "artists", "items"); ok {
+		for _, a := range items {
+			if artistMap, ok := a.(map[string]interface{}); ok {
+				if artistName, ok := lookupString(artistMap, "profile", "name"); ok {
+					artists = append(artists, artistName)
+				}
+			}
+		}
+	}
+	artist := strings.Join(artists, ", ")
+	if artist == "" {
+		artist = data.Name
+	}
+
+	var duration int
+	if ms, ok := lookupFloat(track, "duration", "totalMilliseconds"); ok {
+		duration = int(ms) / 1000
+	}
+
+	image := data.Image
+	if sources, ok := lookupSlice(track, "albumOfTrack", "coverArt", "sources"); ok {
+		if img := s.LargestImage(sources); img != "" {
+			image = img
+		}
+	}
+
+	info := TrackInfo{
+		Name:     name,
+		Artist:   artist,
+		URL:      fmt.Sprintf("https://open.spotify.com/track/%s", parts[len(parts)-1]),
+		Duration: duration,
+		Image:    image,
+	}
+	s.ExtractTrackDetails(track, &info)
+	data.Tracks = append(data.Tracks, info)
+}
+
+func (s *SpotifyService) ExtractArtistReleases(entity map[string]interface{}, group string) []ArtistRelease {
+	items, ok := lookupSlice(entity, "discography", group, "items")
+	if !ok {
+		return nil
+	}
+
+	var releases []ArtistRelease
+	for _, item := range items {
+		itemMap, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		// Each group item wraps its release(s) in releases.items.
+		inner, ok := lookupSlice(itemMap, "releases", "items")
+		if !ok {
+			inner = []interface{}{itemMap}
+		}
+
+		for _, r := range inner {
+			relMap, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if release, ok := s.ParseArtistRelease(relMap); ok {
+				releases = append(releases, release)
+			}
+		}
+	}
+	return releases
+}
+
+func (s *SpotifyService) ParseArtistRelease(relMap map[string]interface{}) (ArtistRelease, bool) {
+	release := ArtistRelease{}
+	release.Name, _ = relMap["name"].(string)
+	release.ID, _ = relMap["id"].(string)
+	if release.ID == "" {
+		if uri, ok := relMap["uri"].(string); ok {
+			parts := strings.Split(uri, ":")
+			release.ID = parts[len(parts)-1]
+		}
+	}
+	if release.Name == "" || release.ID == "" {
+		return release, false
+	}
+
+	release.URL = fmt.Sprintf("https://open.spotify.com/album/%s", release.ID)
+	if typ, ok := relMap["type"].(string); ok {
+		release.Type = strings.ToLower(typ)
+	}
+
+	if sources, ok := lookupSlice(relMap, "coverArt", "sources"); ok {
+		release.Image = s.LargestImage(sources)
+	}
+
+	if iso, ok := lookupString(relMap, "date", "isoString"); ok && iso != "" {
+		release.ReleaseDate = strings.Split(iso, "T")[0]
+	} else if year, ok := lookupFloat(relMap, "date", "year"); ok {
+		release.ReleaseDate = strconv.Itoa(int(year))
+	}
+
+	if total, ok := lookupFloat(relMap, "tracks", "totalCount"); ok {
+		release.TotalTracks = int(total)
+	}
+
+	return release, true
+}
+
+func (s *SpotifyService) ParseArtistEmbedData(entity map[string]interface{}, data *SpotifyData) {
+	if data.Artist == "" {
+		data.Artist = data.Name
+	}
+	if len(data.Tracks) == 0 {
+		s.ProcessTrackList(entity, data)
+	}
+}
+
+func (s *SpotifyService) ExtractMonthlyListeners(description string) int {
+	matches := MonthlyListenersRegex.FindStringSubmatch(description)
+	if len(matches) < 3 {
+		return 0
+	}
+
+	number := strings.ReplaceAll(matches[1], ",", "")
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0
+	}
+
+	switch strings.ToUpper(matches[2]) {
+	case "K":
+		value *= 1e3
+	case "M":
+		value *= 1e6
+	case "B":
+		value *= 1e9
+	}
+	return int(value)
+}
@@ -0,0 +1,76 @@
+package spotify
+
+import (
+	"net/url"
+	"sync"
+)
+
+const (
+	DefaultWorkers   = 8
+	DefaultHostLimit = 4
+)
+
+type hostLimiter struct {
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+}
+
+func (l *hostLimiter) acquire(host string, limit int) func() {
+	l.mu.Lock()
+	if l.slots == nil {
+		l.slots = map[string]chan struct{}{}
+	}
+	slot, ok := l.slots[host]
+	if !ok {
+		slot = make(chan struct{}, limit)
+		l.slots[host] = slot
+	}
+	l.mu.Unlock()
+
+	slot <- struct{}{}
+	return func() { <-slot }
+}
+
+// AcquireHost blocks until a request to the host of rawURL may start and
+// returns the function that releases it.
+func (s *SpotifyService) AcquireHost(rawURL string) func() {
+	host := rawURL
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+
+	limit := s.HostLimit
+	if limit <= 0 {
+		limit = DefaultHostLimit
+	}
+	return s.limiter.acquire(host, limit)
+}
+
+// ForEach runs fn for every index in [0, n) on at most Workers goroutines.
+func (s *SpotifyService) ForEach(n int, fn func(i int)) {
+	workers := s.Workers
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	if workers > n {
+		workers = n
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -9,30 +9,50 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/Beesonn/dlkitgo/errkind"
 	"github.com/PuerkitoBio/goquery"
 )
 
 type TrackInfo struct {
-	Name        string `json:"name"`
-	Artist      string `json:"artist"`
-	PreviewURL  string `json:"preview_url"`
-	URL         string `json:"url"`
-	Duration    int    `json:"duration"`
-	ReleaseDate string `json:"release_date"`
-	Image       string `json:"image"`
+	Name        string        `json:"name"`
+	Artist      string        `json:"artist"`
+	PreviewURL  string        `json:"preview_url"`
+	URL         string        `json:"url"`
+	Duration    int           `json:"duration"`
+	ReleaseDate string        `json:"release_date"`
+	Image       string        `json:"image"`
+	SpotifyID   string        `json:"spotify_id,omitempty"`
+	Artists     []TrackArtist `json:"artists,omitempty"`
+	Album       string        `json:"album,omitempty"`
+	AlbumArtist string        `json:"album_artist,omitempty"`
+	AlbumURL    string        `json:"album_url,omitempty"`
+	DiscNumber  int           `json:"disc_number,omitempty"`
+	TrackNumber int           `json:"track_number,omitempty"`
+	ISRC        string        `json:"isrc,omitempty"`
+	Explicit    bool          `json:"explicit,omitempty"`
+	Popularity  int           `json:"popularity,omitempty"`
 }
 
 type SpotifyData struct {
-	Type        string      `json:"type"`
-	Name        string      `json:"name"`
-	Artist      string      `json:"artist"`
-	SpotifyID   string      `json:"spotify_id"`
-	URL         string      `json:"url"`
-	Image       string      `json:"image"`
-	PreviewURL  string      `json:"preview_url,omitempty"`
-	Tracks      []TrackInfo `json:"tracks"`
-	Duration    int         `json:"duration,omitempty"`
-	ReleaseDate string      `json:"release_date,omitempty"`
+	Type             string          `json:"type"`
+	Name             string          `json:"name"`
+	Artist           string          `json:"artist"`
+	SpotifyID        string          `json:"spotify_id"`
+	URL              string          `json:"url"`
+	Image            string          `json:"image"`
+	PreviewURL       string          `json:"preview_url,omitempty"`
+	Tracks           []TrackInfo     `json:"tracks"`
+	Duration         int             `json:"duration,omitempty"`
+	ReleaseDate      string          `json:"release_date,omitempty"`
+	MonthlyListeners int             `json:"monthly_listeners,omitempty"`
+	Followers        int             `json:"followers,omitempty"`
+	Albums           []ArtistRelease `json:"albums,omitempty"`
+	Singles          []ArtistRelease `json:"singles,omitempty"`
+	Compilations     []ArtistRelease `json:"compilations,omitempty"`
+	Publisher        string          `json:"publisher,omitempty"`
+	Description      string          `json:"description,omitempty"`
+	TotalEpisodes    int             `json:"total_episodes,omitempty"`
+	Episodes         []EpisodeInfo   `json:"episodes,omitempty"`
 }
 
 type LdJson struct {
@@ -42,6 +62,8 @@ type LdJson struct {
 	Description string          `json:"description,omitempty"`
 	Image       []string        `json:"image"`
 	ByArtist    json.RawMessage `json:"byArtist"`
+	Author      json.RawMessage `json:"author,omitempty"`
+	Publisher   json.RawMessage `json:"publisher,omitempty"`
 	Audio       struct {
 		ContentURL string `json:"contentUrl"`
 		Duration   string `json:"duration,omitempty"`
@@ -51,6 +73,13 @@ type LdJson struct {
 	ReleaseOf struct {
 		DatePublished string `json:"datePublished,omitempty"`
 	} `json:"releaseOf,omitempty"`
+	AssociatedMedia struct {
+		ContentURL string `json:"contentUrl"`
+	} `json:"associatedMedia,omitempty"`
+	PartOfSeries struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"partOfSeries,omitempty"`
 	Track []struct {
 		ItemListElement []struct {
 			Item struct {
@@ -73,14 +102,22 @@ type ArtistObj struct {
 	Name string `json:"name"`
 }
 
-func (s *SpotifyService) GetInfo(url string) (SpotifyData, error) {
+func (s *SpotifyService) GetInfo(url string, typeHint ...string) (SpotifyData, error) {
 	data := SpotifyData{
 		Type:   "unknown",
 		Tracks: []TrackInfo{},
 		URL:    url,
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	ref, err := s.ResolveRef(url, typeHint...)
+	if err != nil {
+		return data, err
+	}
+	data.Type = ref.Type
+	data.SpotifyID = ref.ID
+	data.URL = ref.URL()
+
+	req, err := http.NewRequest("GET", data.URL, nil)
 	if err != nil {
 		return data, err
 	}
@@ -88,26 +125,21 @@ func (s *SpotifyService) GetInfo(url string) (SpotifyData, error) {
 
 	resp, err := s.Client.Do(req)
 	if err != nil {
-		return data, errors.New("Invalid URL or ID")
+		return data, fmt.Errorf("failed to fetch %s: %w", data.URL, err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return data, errors.New("Invalid URL or ID")
-	}
-
-	data.URL = resp.Request.URL.String()
-	re := regexp.MustCompile(`/(track|album|playlist)/([a-zA-Z0-9]+)`)
-	matches := re.FindStringSubmatch(data.URL)
-
-	if len(matches) == 3 {
-		data.Type = matches[1]
-		data.SpotifyID = matches[2]
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return data, errkind.New(errkind.ErrNotFound, "Spotify %s %s not found", ref.Type, ref.ID)
+	default:
+		return data, fmt.Errorf("Spotify returned status %d", resp.StatusCode)
 	}
 
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
-		return data, errors.New("Invalid URL or ID")
+		return data, fmt.Errorf("failed to parse HTML: %w", err)
 	}
 
 	if imgSel := doc.Find(`meta[property="og:image"]`); imgSel.Length() > 0 {
@@ -116,10 +148,22 @@ func (s *SpotifyService) GetInfo(url string) (SpotifyData, error) {
 		}
 	}
 
+	if data.Type == "artist" {
+		if descSel := doc.Find(`meta[property="og:description"]`); descSel.Length() > 0 {
+			if desc, exists := descSel.Attr("content"); exists {
+				data.MonthlyListeners = s.ExtractMonthlyListeners(desc)
+			}
+		}
+	}
+
 	doc.Find("script#__NEXT_DATA__").Each(func(i int, sel *goquery.Selection) {
 		s.ParseNextData(sel.Text(), &data)
 	})
 
+	doc.Find("script#initial-state").Each(func(i int, sel *goquery.Selection) {
+		s.ParseInitialState(sel.Text(), &data)
+	})
+
 	doc.Find("script[type='application/ld+json']").Each(func(i int, sel *goquery.Selection) {
 		s.ProcessLdJsonScript(sel.Text(), &data)
 	})
@@ -128,8 +172,27 @@ func (s *SpotifyService) GetInfo(url string) (SpotifyData, error) {
 		s.FetchEmbedData(&data)
 	}
 
+	if data.Type == "artist" && len(data.Tracks) == 0 {
+		s.FetchEmbedData(&data)
+	}
+
+	if (data.Type == "show" || data.Type == "episode") && len(data.Episodes) == 0 {
+		s.FetchEmbedData(&data)
+	}
+
+	if data.Type == "show" {
+		// The embedded episodes are kept if paging fails.
+		s.FetchAllEpisodes(&data)
+	}
+
 	if data.Type == "playlist" || data.Type == "album" {
-		s.EnhanceTrackData(&data)
+		if err := s.FetchAllTracks(&data); err != nil {
+			s.EnhanceTrackData(&data)
+		}
+	}
+
+	if data.Type == "track" && len(data.Tracks) > 0 {
+		s.FetchTrackDetails(&data.Tracks[0])
 	}
 
 	return data, nil
@@ -140,43 +203,30 @@ func (s *SpotifyService) EnhanceTrackData(data *SpotifyData) {
 		return
 	}
 
-	var wg sync.WaitGroup
 	var mu sync.Mutex
+	s.ForEach(len(data.Tracks), func(idx int) {
+		release := s.AcquireHost(data.Tracks[idx].URL)
+		trackData, err := s.GetInfo(data.Tracks[idx].URL)
+		release()
+		if err != nil {
+			return
+		}
 
-	for i := range data.Tracks {
-		wg.Add(1)
-		go func(idx int) {
-			defer wg.Done()
-
-			trackData, err := s.GetInfo(data.Tracks[idx].URL)
-			if err != nil {
-				return
-			}
-
-			mu.Lock()
-			if trackData.Name != "" {
-				data.Tracks[idx].Name = trackData.Name
-			}
-			if trackData.Artist != "" {
-				data.Tracks[idx].Artist = trackData.Artist
-			}
-			if trackData.ReleaseDate != "" {
-				data.Tracks[idx].ReleaseDate = trackData.ReleaseDate
-			}
-			if trackData.Image != "" {
-				data.Tracks[idx].Image = trackData.Image
-			}
-			if trackData.PreviewURL != "" {
-				data.Tracks[idx].PreviewURL = trackData.PreviewURL
-			}
-			if trackData.Duration > 0 {
-				data.Tracks[idx].Duration = trackData.Duration
-			}
-			mu.Unlock()
-		}(i)
-	}
-
-	wg.Wait()
+		mu.Lock()
+		if len(trackData.Tracks) > 0 {
+			s.MergeTrackInfo(&data.Tracks[idx], trackData.Tracks[0])
+		} else {
+			s.MergeTrackInfo(&data.Tracks[idx], TrackInfo{
+				Name:        trackData.Name,
+				Artist:      trackData.Artist,
+				ReleaseDate: trackData.ReleaseDate,
+				Image:       trackData.Image,
+				PreviewURL:  trackData.PreviewURL,
+				Duration:    trackData.Duration,
+			})
+		}
+		mu.Unlock()
+	})
 }
 
 func (s *SpotifyService) ProcessLdJsonScript(scriptText string, data *SpotifyData) {
@@ -209,6 +259,10 @@ func (s *SpotifyService) ProcessLdJson(ld *LdJson, data *SpotifyData) {
 		if data.Type == "playlist" {
 			s.ProcessMusicPlaylist(ld, data)
 		}
+	case "PodcastSeries", "PodcastEpisode":
+		if data.Type == "show" || data.Type == "episode" {
+			s.ProcessPodcastLdJson(ld, data)
+		}
 	}
 }
 
@@ -324,6 +378,9 @@ func (s *SpotifyService) FetchEmbedData(data *SpotifyData) {
 	embedUrl := strings.Replace(data.URL, "/playlist/", "/embed/playlist/", 1)
 	embedUrl = strings.Replace(embedUrl, "/album/", "/embed/album/", 1)
 	embedUrl = strings.Replace(embedUrl, "/track/", "/embed/track/", 1)
+	embedUrl = strings.Replace(embedUrl, "/artist/", "/embed/artist/", 1)
+	embedUrl = strings.Replace(embedUrl, "/show/", "/embed/show/", 1)
+	embedUrl = strings.Replace(embedUrl, "/episode/", "/embed/episode/", 1)
 
 	req, _ := http.NewRequest("GET", embedUrl, nil)
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
@@ -373,6 +430,12 @@ func (s *SpotifyService) ParseNextData(jsonStr string, data *SpotifyData) {
 		s.ParseSingleTrackData(entity, data)
 	} else if data.Type == "playlist" || data.Type == "album" {
 		s.ParsePlaylistOrAlbumData(entity, data)
+	} else if data.Type == "artist" {
+		s.ParseArtistEmbedData(entity, data)
+	} else if data.Type == "show" {
+		s.ParseShowEmbedData(entity, data)
+	} else if data.Type == "episode" {
+		s.ParseEpisodeEmbedData(entity, data)
 	}
 }
 
@@ -522,7 +585,7 @@ func (s *SpotifyService) CreateSingleTrackEntry(entity map[string]interface{}, d
 		if len(parts) > 1 {
 			trackId := parts[len(parts)-1]
 			trackLink := fmt.Sprintf("https://open.spotify.com/track/%s", trackId)
-			data.Tracks = append(data.Tracks, TrackInfo{
+			track := TrackInfo{
 				Name:        data.Name,
 				Artist:      data.Artist,
 				PreviewURL:  data.PreviewURL,
@@ -530,7 +593,9 @@ func (s *SpotifyService) CreateSingleTrackEntry(entity map[string]interface{}, d
 				Duration:    data.Duration,
 				ReleaseDate: data.ReleaseDate,
 				Image:       data.Image,
-			})
+			}
+			s.ExtractTrackDetails(entity, &track)
+			data.Tracks = append(data.Tracks, track)
 		}
 	}
 }
@@ -587,7 +652,7 @@ func (s *SpotifyService) ProcessSingleTrack(trackMap map[string]interface{}, dat
 	if len(parts) > 1 {
 		trackId := parts[len(parts)-1]
 		trackLink := fmt.Sprintf("https://open.spotify.com/track/%s", trackId)
-		data.Tracks = append(data.Tracks, TrackInfo{
+		track := TrackInfo{
 			Name:        name,
 			Artist:      artist,
 			PreviewURL:  previewUrl,
@@ -595,7 +660,14 @@ func (s *SpotifyService) ProcessSingleTrack(trackMap map[string]interface{}, dat
 			Duration:    duration,
 			ReleaseDate: data.ReleaseDate,
 			Image:       data.Image,
-		})
+		}
+		s.ExtractTrackDetails(trackMap, &track)
+		if data.Type == "album" && track.Album == "" {
+			track.Album = data.Name
+			track.AlbumArtist = data.Artist
+			track.AlbumURL = data.URL
+		}
+		data.Tracks = append(data.Tracks, track)
 	}
 }
 
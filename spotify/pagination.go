@@ -0,0 +1,296 @@
+package spotify
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type apiImage struct {
+	URL string `json:"url"`
+}
+
+type apiArtist struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type apiTrack struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	DurationMs  int         `json:"duration_ms"`
+	PreviewURL  string      `json:"preview_url"`
+	IsLocal     bool        `json:"is_local"`
+	Explicit    bool        `json:"explicit"`
+	Popularity  int         `json:"popularity"`
+	DiscNumber  int         `json:"disc_number"`
+	TrackNumber int         `json:"track_number"`
+	Artists     []apiArtist `json:"artists"`
+	ExternalIDs struct {
+		ISRC string `json:"isrc"`
+	} `json:"external_ids"`
+	Album *apiAlbum `json:"album"`
+}
+
+type apiTrackPage struct {
+	Items []struct {
+		// Playlist pages wrap each track, album pages do not.
+		Track *apiTrack `json:"track"`
+		apiTrack
+	} `json:"items"`
+	Total int    `json:"total"`
+	Next  string `json:"next"`
+}
+
+type apiAlbum struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	ReleaseDate string      `json:"release_date"`
+	Images      []apiImage  `json:"images"`
+	Artists     []apiArtist `json:"artists"`
+}
+
+// TrackPageSize is the number of tracks requested per page.
+var TrackPageSize = 50
+
+// ErrTruncated is yielded by IterTracks after the tracks embedded in the
+// page itself when the web API could not be paged, as the list may be
+// missing the rest of a large collection.
+var ErrTruncated = errors.New("track list may be incomplete")
+
+// IterTracks yields every track of a playlist or album page by page, so very
+// large collections never have to be held in memory at once. Any other URL
+// yields the tracks returned by GetInfo. When paging fails the remaining
+// tracks GetInfo found are yielded instead, followed by ErrTruncated.
+func (s *SpotifyService) IterTracks(spotifyURL string, typeHint ...string) iter.Seq2[TrackInfo, error] {
+	return func(yield func(TrackInfo, error) bool) {
+		ref, err := s.ResolveRef(spotifyURL, typeHint...)
+		if err != nil {
+			yield(TrackInfo{}, err)
+			return
+		}
+
+		if ref.Type != "playlist" && ref.Type != "album" {
+			info, err := s.GetInfo(ref.URL())
+			if err != nil {
+				yield(TrackInfo{}, err)
+				return
+			}
+			for _, track := range info.Tracks {
+				if !yield(track, nil) {
+					return
+				}
+			}
+			return
+		}
+
+		yielded := 0
+		fallback := func(apiErr error) {
+			info, err := s.GetInfo(ref.URL())
+			if err != nil {
+				yield(TrackInfo{}, err)
+				return
+			}
+			for _, track := range info.Tracks[min(yielded, len(info.Tracks)):] {
+				if !yield(track, nil) {
+					return
+				}
+			}
+			yield(TrackInfo{}, fmt.Errorf("%w: %w", ErrTruncated, apiErr))
+		}
+
+		defaults := SpotifyData{Type: ref.Type, SpotifyID: ref.ID}
+		if defaults.Type == "album" {
+			var album apiAlbum
+			if err := s.APIGet("/albums/"+defaults.SpotifyID, nil, &album); err != nil {
+				fallback(err)
+				return
+			}
+			defaults.Name = album.Name
+			defaults.ReleaseDate = album.ReleaseDate
+			if len(album.Artists) > 0 {
+				defaults.Artist = album.Artists[0].Name
+			}
+			if len(album.Images) > 0 {
+				defaults.Image = album.Images[0].URL
+			}
+		}
+
+		for offset := 0; ; {
+			tracks, total, err := s.FetchTrackPage(&defaults, offset)
+			if err != nil {
+				fallback(err)
+				return
+			}
+			for _, track := range tracks {
+				if !yield(track, nil) {
+					return
+				}
+				yielded++
+			}
+
+			offset += TrackPageSize
+			if offset >= total {
+				return
+			}
+		}
+	}
+}
+
+// FetchAllTracks replaces the tracks embedded in the page with the complete
+// list from the web API. The embedded tracks are kept if paging fails.
+func (s *SpotifyService) FetchAllTracks(data *SpotifyData) error {
+	if (data.Type != "playlist" && data.Type != "album") || data.SpotifyID == "" {
+		return errors.New("only playlists and albums can be paginated")
+	}
+
+	var tracks []TrackInfo
+	for offset := 0; ; {
+		page, total, err := s.FetchTrackPage(data, offset)
+		if err != nil {
+			return err
+		}
+		tracks = append(tracks, page...)
+
+		offset += TrackPageSize
+		if offset >= total {
+			break
+		}
+	}
+
+	if len(tracks) < len(data.Tracks) {
+		return fmt.Errorf("paginated %d tracks but the page embeds %d", len(tracks), len(data.Tracks))
+	}
+
+	data.Tracks = tracks
+	return nil
+}
+
+func (s *SpotifyService) FetchTrackPage(data *SpotifyData, offset int) ([]TrackInfo, int, error) {
+	query := url.Values{}
+	query.Set("offset", strconv.Itoa(offset))
+	query.Set("limit", strconv.Itoa(TrackPageSize))
+
+	path := fmt.Sprintf("/%ss/%s/tracks", data.Type, data.SpotifyID)
+
+	var page apiTrackPage
+	if err := s.APIGet(path, query, &page); err != nil {
+		return nil, 0, err
+	}
+
+	tracks := make([]TrackInfo, 0, len(page.Items))
+	for _, item := range page.Items {
+		t := item.Track
+		if t == nil {
+			t = &item.apiTrack
+		}
+		// Local files and removed tracks have no ID and cannot be streamed.
+		if t.ID == "" || t.IsLocal {
+			continue
+		}
+		tracks = append(tracks, s.TrackFromAPI(t, data))
+	}
+
+	if data.Type == "album" {
+		// Failing to add the extra fields still leaves a usable page.
+		s.FetchTrackExtras(tracks)
+	}
+
+	return tracks, page.Total, nil
+}
+
+// TrackBatchSize is the most tracks the web API returns per lookup.
+const TrackBatchSize = 50
+
+// FetchTrackExtras fills in the ISRC and popularity of tracks, which album
+// pages leave out, looking the tracks up in batches.
+func (s *SpotifyService) FetchTrackExtras(tracks []TrackInfo) error {
+	for start := 0; start < len(tracks); start += TrackBatchSize {
+		batch := tracks[start:min(start+TrackBatchSize, len(tracks))]
+
+		ids := make([]string, len(batch))
+		for i, t := range batch {
+			ids[i] = t.SpotifyID
+		}
+		query := url.Values{}
+		query.Set("ids", strings.Join(ids, ","))
+
+		var resp struct {
+			Tracks []*apiTrack `json:"tracks"`
+		}
+		if err := s.APIGet("/tracks", query, &resp); err != nil {
+			return err
+		}
+
+		byID := make(map[string]*apiTrack, len(resp.Tracks))
+		for _, t := range resp.Tracks {
+			// Unknown IDs come back as null.
+			if t != nil {
+				byID[t.ID] = t
+			}
+		}
+		for i := range batch {
+			if t, ok := byID[batch[i].SpotifyID]; ok {
+				batch[i].ISRC = t.ExternalIDs.ISRC
+				batch[i].Popularity = t.Popularity
+			}
+		}
+	}
+	return nil
+}
+
+func (s *SpotifyService) TrackFromAPI(t *apiTrack, data *SpotifyData) TrackInfo {
+	names := make([]string, 0, len(t.Artists))
+	artists := make([]TrackArtist, 0, len(t.Artists))
+	for _, a := range t.Artists {
+		names = append(names, a.Name)
+		artist := TrackArtist{ID: a.ID, Name: a.Name}
+		if a.ID != "" {
+			artist.URL = fmt.Sprintf("https://open.spotify.com/artist/%s", a.ID)
+		}
+		artists = append(artists, artist)
+	}
+
+	track := TrackInfo{
+		Name:        t.Name,
+		Artist:      strings.Join(names, ", "),
+		PreviewURL:  t.PreviewURL,
+		URL:         fmt.Sprintf("https://open.spotify.com/track/%s", t.ID),
+		Duration:    t.DurationMs / 1000,
+		ReleaseDate: data.ReleaseDate,
+		Image:       data.Image,
+		SpotifyID:   t.ID,
+		Artists:     artists,
+		DiscNumber:  t.DiscNumber,
+		TrackNumber: t.TrackNumber,
+		ISRC:        t.ExternalIDs.ISRC,
+		Explicit:    t.Explicit,
+		Popularity:  t.Popularity,
+	}
+
+	if t.Album != nil {
+		track.Album = t.Album.Name
+		if t.Album.ID != "" {
+			track.AlbumURL = fmt.Sprintf("https://open.spotify.com/album/%s", t.Album.ID)
+		}
+		if len(t.Album.Artists) > 0 {
+			track.AlbumArtist = t.Album.Artists[0].Name
+		}
+		if t.Album.ReleaseDate != "" {
+			track.ReleaseDate = t.Album.ReleaseDate
+		}
+		if len(t.Album.Images) > 0 {
+			track.Image = t.Album.Images[0].URL
+		}
+	} else if data.Type == "album" {
+		// Album pages list simplified tracks without their album.
+		track.Album = data.Name
+		track.AlbumArtist = data.Artist
+		track.AlbumURL = fmt.Sprintf("https://open.spotify.com/album/%s", data.SpotifyID)
+	}
+
+	return track
+}
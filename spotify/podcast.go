@@ -0,0 +1,480 @@
+package spotify
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type EpisodeInfo struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Show        string `json:"show,omitempty"`
+	Publisher   string `json:"publisher,omitempty"`
+	URL         string `json:"url"`
+	Image       string `json:"image"`
+	Duration    int    `json:"duration"`
+	ReleaseDate string `json:"release_date"`
+	PreviewURL  string `json:"preview_url,omitempty"`
+	AudioURL    string `json:"audio_url,omitempty"`
+}
+
+type EpisodePage struct {
+	ShowID   string        `json:"show_id"`
+	Offset   int           `json:"offset"`
+	Limit    int           `json:"limit"`
+	Total    int           `json:"total"`
+	HasMore  bool          `json:"has_more"`
+	Episodes []EpisodeInfo `json:"episodes"`
+}
+
+type apiEpisode struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	ReleaseDate     string `json:"release_date"`
+	DurationMs      int    `json:"duration_ms"`
+	AudioPreviewURL string `json:"audio_preview_url"`
+	Images          []struct {
+		URL string `json:"url"`
+	} `json:"images"`
+}
+
+type apiEpisodePage struct {
+	Items  []apiEpisode `json:"items"`
+	Offset int          `json:"offset"`
+	Limit  int          `json:"limit"`
+	Total  int          `json:"total"`
+	Next   string       `json:"next"`
+}
+
+// GetShowEpisodes returns one page of a show's episodes, newest first.
+func (s *SpotifyService) GetShowEpisodes(showID string, offset, limit int) (EpisodePage, error) {
+	page := EpisodePage{ShowID: showID, Offset: offset, Limit: limit}
+	if showID == "" {
+		return page, errors.New("show id cannot be empty")
+	}
+	if limit <= 0 || limit > 50 {
+		limit = 50
+		page.Limit = limit
+	}
+
+	query := url.Values{}
+	query.Set("offset", strconv.Itoa(offset))
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("market", "US")
+
+	var resp apiEpisodePage
+	if err := s.APIGet("/shows/"+showID+"/episodes", query, &resp); err != nil {
+		return page, err
+	}
+
+	page.Total = resp.Total
+	page.HasMore = resp.Next != ""
+	for _, ep := range resp.Items {
+		if ep.ID == "" {
+			continue
+		}
+		episode := EpisodeInfo{
+			ID:          ep.ID,
+			Name:        ep.Name,
+			Description: ep.Description,
+			URL:         fmt.Sprintf("https://open.spotify.com/episode/%s", ep.ID),
+			Duration:    ep.DurationMs / 1000,
+			ReleaseDate: ep.ReleaseDate,
+			PreviewURL:  ep.AudioPreviewURL,
+		}
+		if len(ep.Images) > 0 {
+			episode.Image = ep.Images[0].URL
+		}
+		page.Episodes = append(page.Episodes, episode)
+	}
+
+	return page, nil
+}
+
+// EpisodePageSize is the number of episodes requested per page.
+var EpisodePageSize = 50
+
+// DefaultMaxEpisodes is the number of episodes a show lists by default.
+const DefaultMaxEpisodes = 50
+
+func (s *SpotifyService) maxEpisodes() int {
+	if s.MaxEpisodes == 0 {
+		return DefaultMaxEpisodes
+	}
+	return s.MaxEpisodes
+}
+
+// FetchAllEpisodes replaces the episodes embedded in the page with the
+// newest MaxEpisodes from the web API. The embedded episodes are kept if
+// paging fails, and any audio URLs they carry are carried over.
+func (s *SpotifyService) FetchAllEpisodes(data *SpotifyData) error {
+	if data.Type != "show" || data.SpotifyID == "" {
+		return errors.New("only shows can be paginated")
+	}
+
+	audio := map[string]string{}
+	for _, ep := range data.Episodes {
+		if ep.AudioURL != "" {
+			audio[ep.ID] = ep.AudioURL
+		}
+	}
+
+	limit := s.maxEpisodes()
+	var episodes []EpisodeInfo
+	total := 0
+	for offset := 0; limit < 0 || len(episodes) < limit; {
+		page, err := s.GetShowEpisodes(data.SpotifyID, offset, EpisodePageSize)
+		if err != nil {
+			return err
+		}
+		total = page.Total
+		for _, ep := range page.Episodes {
+			ep.Show = data.Name
+			ep.Publisher = data.Publisher
+			ep.AudioURL = audio[ep.ID]
+			if ep.Image == "" {
+				ep.Image = data.Image
+			}
+			episodes = append(episodes, ep)
+		}
+
+		offset += page.Limit
+		if !page.HasMore || offset >= page.Total {
+			break
+		}
+	}
+
+	if limit > 0 && len(episodes) > limit {
+		episodes = episodes[:limit]
+	}
+	want := len(data.Episodes)
+	if limit > 0 {
+		want = min(want, limit)
+	}
+	if len(episodes) < want {
+		return fmt.Errorf("paginated %d episodes but the page embeds %d", len(episodes), want)
+	}
+
+	data.Episodes = episodes
+	if data.TotalEpisodes == 0 {
+		data.TotalEpisodes = total
+	}
+	return nil
+}
+
+// FetchEpisodeAudio looks up the audio URL of every episode that lacks one.
+// Show listings never include it, only the episode's own page does.
+func (s *SpotifyService) FetchEpisodeAudio(data *SpotifyData) {
+	// Each worker writes only its own episode.
+	s.ForEach(len(data.Episodes), func(idx int) {
+		episode := &data.Episodes[idx]
+		if episode.AudioURL != "" || episode.URL == "" {
+			return
+		}
+
+		release := s.AcquireHost(episode.URL)
+		info, err := s.GetInfo(episode.URL)
+		release()
+		if err != nil || len(info.Episodes) == 0 {
+			return
+		}
+		episode.AudioURL = info.Episodes[0].AudioURL
+	})
+}
+
+func (s *SpotifyService) ParseShowOverview(entity map[string]interface{}, data *SpotifyData) {
+	if name, ok := entity["name"].(string); ok && name != "" {
+		data.Name = name
+	}
+	if publisher, ok := lookupString(entity, "publisher", "name"); ok && publisher != "" {
+		data.Publisher = publisher
+		data.Artist = publisher
+	}
+	if desc, ok := entity["description"].(string); ok && desc != "" {
+		data.Description = desc
+	}
+	if sources, ok := lookupSlice(entity, "coverArt", "sources"); ok {
+		if img := s.LargestImage(sources); img != "" {
+			data.Image = img
+		}
+	}
+
+	if total, ok := lookupFloat(entity, "episodesV2", "totalCount"); ok {
+		data.TotalEpisodes = int(total)
+	}
+
+	items, _ := lookupSlice(entity, "episodesV2", "items")
+	if len(data.Episodes) > 0 {
+		return
+	}
+	for _, item := range items {
+		itemMap, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		episodeMap, ok := lookupMap(itemMap, "entity", "data")
+		if !ok {
+			continue
+		}
+		if episode, ok := s.ParseEpisodeEntity(episodeMap); ok {
+			episode.Show = data.Name
+			episode.Publisher = data.Publisher
+			data.Episodes = append(data.Episodes, episode)
+		}
+	}
+}
+
+func (s *SpotifyService) ParseEpisodeOverview(entity map[string]interface{}, data *SpotifyData) {
+	episode, ok := s.ParseEpisodeEntity(entity)
+	if !ok {
+		return
+	}
+
+	data.Name = episode.Name
+	data.Description = episode.Description
+	data.Duration = episode.Duration
+	data.ReleaseDate = episode.ReleaseDate
+	if episode.Image != "" {
+		data.Image = episode.Image
+	}
+	if episode.PreviewURL != "" {
+		data.PreviewURL = episode.PreviewURL
+	}
+	data.Publisher = episode.Publisher
+	data.Artist = episode.Show
+	data.Episodes = []EpisodeInfo{episode}
+}
+
+func (s *SpotifyService) ParseEpisodeEntity(entity map[string]interface{}) (EpisodeInfo, bool) {
+	episode := EpisodeInfo{}
+	episode.Name, _ = entity["name"].(string)
+
+	uri, _ := entity["uri"].(string)
+	if uri == "" {
+		uri, _ = entity["_uri"].(string)
+	}
+	if parts := strings.Split(uri, ":"); len(parts) > 2 {
+		episode.ID = parts[len(parts)-1]
+	}
+	if episode.Name == "" || episode.ID == "" {
+		return episode, false
+	}
+	episode.URL = fmt.Sprintf("https://open.spotify.com/episode/%s", episode.ID)
+
+	if desc, ok := entity["description"].(string); ok {
+		episode.Description = desc
+	}
+	if ms, ok := lookupFloat(entity, "duration", "totalMilliseconds"); ok {
+		episode.Duration = int(ms) / 1000
+	} else if ms, ok := entity["duration"].(float64); ok {
+		episode.Duration = int(ms) / 1000
+	}
+	if iso, ok := lookupString(entity, "releaseDate", "isoString"); ok {
+		episode.ReleaseDate = strings.Split(iso, "T")[0]
+	}
+	if sources, ok := lookupSlice(entity, "coverArt", "sources"); ok {
+		episode.Image = s.LargestImage(sources)
+	}
+	if preview, ok := lookupString(entity, "audioPreview", "url"); ok {
+		episode.PreviewURL = preview
+	}
+	if show, ok := lookupString(entity, "podcastV2", "data", "name"); ok {
+		episode.Show = show
+	}
+	if publisher, ok := lookupString(entity, "podcastV2", "data", "publisher", "name"); ok {
+		episode.Publisher = publisher
+	}
+
+	episode.AudioURL = s.ExtractOpenAudioURL(entity)
+
+	return episode, true
+}
+
+// ExtractOpenAudioURL returns the direct audio file of an externally hosted
+// episode. Spotify-exclusive episodes are DRM protected and have none.
+func (s *SpotifyService) ExtractOpenAudioURL(entity map[string]interface{}) string {
+	items, ok := lookupSlice(entity, "audio", "items")
+	if !ok {
+		return ""
+	}
+
+	for _, item := range items {
+		itemMap, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if hosted, ok := itemMap["externallyHosted"].(bool); ok && !hosted {
+			continue
+		}
+		if audioURL, ok := itemMap["url"].(string); ok && strings.HasPrefix(audioURL, "http") {
+			return audioURL
+		}
+	}
+	return ""
+}
+
+func (s *SpotifyService) ParseShowEmbedData(entity map[string]interface{}, data *SpotifyData) {
+	if data.Publisher == "" {
+		if subtitle, ok := entity["subtitle"].(string); ok {
+			data.Publisher = subtitle
+			data.Artist = subtitle
+		}
+	}
+
+	if len(data.Episodes) > 0 {
+		return
+	}
+
+	trackList, _ := entity["trackList"].([]interface{})
+	for _, t := range trackList {
+		trackMap, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name := s.ExtractTrackName(trackMap)
+		uri, _ := trackMap["uri"].(string)
+		parts := strings.Split(uri, ":")
+		if name == "" || len(parts) < 3 {
+			continue
+		}
+		data.Episodes = append(data.Episodes, EpisodeInfo{
+			ID:          parts[len(parts)-1],
+			Name:        name,
+			Show:        data.Name,
+			Publisher:   data.Publisher,
+			URL:         fmt.Sprintf("https://open.spotify.com/episode/%s", parts[len(parts)-1]),
+			Image:       data.Image,
+			Duration:    s.ExtractTrackDurationFromMap(trackMap),
+			PreviewURL:  s.ExtractTrackPreviewURLFromMap(trackMap),
+			ReleaseDate: s.ExtractEpisodeReleaseDate(trackMap),
+		})
+	}
+}
+
+func (s *SpotifyService) ParseEpisodeEmbedData(entity map[string]interface{}, data *SpotifyData) {
+	if data.Artist == "" {
+		if subtitle, ok := entity["subtitle"].(string); ok {
+			data.Artist = subtitle
+		}
+	}
+	s.ExtractTrackDuration(entity, data)
+	s.ExtractTrackPreviewURL(entity, data)
+	s.ExtractTrackReleaseDate(entity, data)
+
+	if len(data.Episodes) == 0 && data.Name != "" {
+		data.Episodes = []EpisodeInfo{{
+			ID:          data.SpotifyID,
+			Name:        data.Name,
+			Show:        data.Artist,
+			URL:         fmt.Sprintf("https://open.spotify.com/episode/%s", data.SpotifyID),
+			Image:       data.Image,
+			Duration:    data.Duration,
+			ReleaseDate: data.ReleaseDate,
+			PreviewURL:  data.PreviewURL,
+			AudioURL:    s.ExtractOpenAudioURL(entity),
+		}}
+	}
+}
+
+func (s *SpotifyService) ExtractEpisodeReleaseDate(trackMap map[string]interface{}) string {
+	if iso, ok := lookupString(trackMap, "releaseDate", "isoString"); ok {
+		return strings.Split(iso, "T")[0]
+	}
+	if date, ok := trackMap["releaseDate"].(string); ok {
+		return date
+	}
+	return ""
+}
+
+func (s *SpotifyService) ProcessPodcastLdJson(ld *LdJson, data *SpotifyData) {
+	if data.Name == "" {
+		data.Name = ld.Name
+	}
+	if data.Description == "" {
+		data.Description = ld.Description
+	}
+	if data.Image == "" && len(ld.Image) > 0 {
+		data.Image = ld.Image[0]
+	}
+	if data.Publisher == "" {
+		if publisher := s.ParseArtistRaw(ld.Author); publisher != "" {
+			data.Publisher = publisher
+		} else {
+			data.Publisher = s.ParseArtistRaw(ld.Publisher)
+		}
+	}
+
+	if ld.Type != "PodcastEpisode" {
+		return
+	}
+
+	if data.ReleaseDate == "" && ld.Date != "" {
+		data.ReleaseDate = strings.Split(ld.Date, "T")[0]
+	}
+	if data.Duration == 0 && ld.Duration != "" {
+		if dur, err := s.ParseDurationToSeconds(ld.Duration); err == nil {
+			data.Duration = dur
+		}
+	}
+	if data.Artist == "" {
+		data.Artist = ld.PartOfSeries.Name
+	}
+
+	if len(data.Episodes) > 0 {
+		if data.Episodes[0].AudioURL == "" {
+			data.Episodes[0].AudioURL = ld.AssociatedMedia.ContentURL
+		}
+		return
+	}
+	data.Episodes = []EpisodeInfo{{
+		ID:          data.SpotifyID,
+		Name:        data.Name,
+		Description: data.Description,
+		Show:        data.Artist,
+		Publisher:   data.Publisher,
+		URL:         data.URL,
+		Image:       data.Image,
+		Duration:    data.Duration,
+		ReleaseDate: data.ReleaseDate,
+		AudioURL:    ld.AssociatedMedia.ContentURL,
+	}}
+}
+
+// EpisodeSources lists the episodes whose audio is openly available, and
+// reports the others as failed. Converter providers only handle music, so
+// they are not consulted.
+func (s *SpotifyService) EpisodeSources(info SpotifyData) ([]TrackSource, []FailedTrack) {
+	sources := []TrackSource{}
+	var failed []FailedTrack
+	for i, ep := range info.Episodes {
+		artist := ep.Show
+		if artist == "" {
+			artist = ep.Publisher
+		}
+		if ep.AudioURL == "" {
+			failed = append(failed, FailedTrack{
+				Index:  i,
+				Title:  ep.Name,
+				Artist: artist,
+				URL:    ep.URL,
+				Error:  "episode audio is not openly available",
+			})
+			continue
+		}
+		sources = append(sources, TrackSource{
+			SpotifyID:   ep.ID,
+			Title:       ep.Name,
+			Artist:      artist,
+			Image:       ep.Image,
+			URL:         ep.AudioURL,
+			Duration:    ep.Duration,
+			ReleaseDate: ep.ReleaseDate,
+		})
+	}
+	return sources, failed
+}
@@ -0,0 +1,51 @@
+package spotify
+
+import "testing"
+
+func TestEpisodeSources(t *testing.T) {
+	info := SpotifyData{
+		Type: "show",
+		Episodes: []EpisodeInfo{
+			{ID: "a", Name: "Open", Show: "Show", URL: "https://open.spotify.com/episode/a", AudioURL: "https://cdn.example.com/a.mp3", Duration: 60},
+			{ID: "b", Name: "Exclusive", Publisher: "Publisher", URL: "https://open.spotify.com/episode/b"},
+		},
+	}
+
+	var s SpotifyService
+	sources, failed := s.EpisodeSources(info)
+	if len(sources) != 1 || sources[0].SpotifyID != "a" || sources[0].URL != "https://cdn.example.com/a.mp3" || sources[0].Artist != "Show" {
+		t.Errorf("sources = %+v", sources)
+	}
+	if len(failed) != 1 || failed[0].Index != 1 || failed[0].Title != "Exclusive" || failed[0].Artist != "Publisher" {
+		t.Errorf("failed = %+v", failed)
+	}
+}
+
+func TestExtractOpenAudioURL(t *testing.T) {
+	var s SpotifyService
+	for _, tc := range []struct {
+		name   string
+		entity map[string]interface{}
+		want   string
+	}{
+		{"no audio", map[string]interface{}{}, ""},
+		{
+			"hosted elsewhere",
+			map[string]interface{}{"audio": map[string]interface{}{"items": []interface{}{
+				map[string]interface{}{"externallyHosted": true, "url": "https://cdn.example.com/ep.mp3"},
+			}}},
+			"https://cdn.example.com/ep.mp3",
+		},
+		{
+			"exclusive",
+			map[string]interface{}{"audio": map[string]interface{}{"items": []interface{}{
+				map[string]interface{}{"externallyHosted": false, "url": "https://audio.spotify.com/ep"},
+			}}},
+			"",
+		},
+	} {
+		if got := s.ExtractOpenAudioURL(tc.entity); got != tc.want {
+			t.Errorf("%s: ExtractOpenAudioURL = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package spotify
 import (
 	"net/http"
 
+	"github.com/Beesonn/dlkitgo/capability"
 	"github.com/Beesonn/dlkitgo/spotify/providers"
 )
 
@@ -18,5 +19,22 @@ func DefaultProviders(client *http.Client) []Provider {
 		&providers.Spotidown{Client: client},
 		&providers.Downloaderize{Client: client},
 		&providers.SpotMate{Client: client},
+		NewYouTubeResolver(client),
 	}
 }
+
+// SetProxyLink routes the download links of providers that support it
+// through wrap, or returns them raw again when wrap is nil.
+func (s *SpotifyService) SetProxyLink(wrap func(rawURL string) string) {
+	for _, provider := range s.Providers {
+		if p, ok := provider.(*providers.Spotidown); ok {
+			p.ProxyLink = wrap
+		}
+	}
+}
+
+// ProvidersFor returns the configured providers that support kind. Only
+// tracks are streamed through providers, episodes come from their feeds.
+func (s *SpotifyService) ProvidersFor(kind string) []Provider {
+	return capability.Filter(s.Providers, kind)
+}
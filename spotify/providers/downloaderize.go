@@ -10,6 +10,8 @@ import (
 	"net/url"
 	"regexp"
 	"strings"
+
+	"github.com/Beesonn/dlkitgo/capability"
 )
 
 type Downloaderize struct {
@@ -24,6 +26,13 @@ func (p *Downloaderize) BaseURL() string {
 	return "https://spotify.downloaderize.com"
 }
 
+func (p *Downloaderize) Capabilities() capability.Capabilities {
+	return capability.Capabilities{
+		Kinds:     []string{capability.Track},
+		AudioOnly: true,
+	}
+}
+
 func (p *Downloaderize) Stream(spotifyURL string) (string, error) {
 	if spotifyURL == "" {
 		return "", errors.New("url cannot be empty")
@@ -6,14 +6,20 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"net/http/cookiejar"
 	"net/url"
 	"regexp"
 	"strings"
+
+	"github.com/Beesonn/dlkitgo/capability"
+	"github.com/Beesonn/dlkitgo/cookies"
 )
 
 type Spotidown struct {
 	Client *http.Client
+	// ProxyLink wraps each download link, for example with proxy.Proxy.Wrap
+	// to serve it through a first-party proxy. Links are returned as they
+	// are when it is nil.
+	ProxyLink func(rawURL string) string
 }
 
 func (p *Spotidown) Name() string {
@@ -24,29 +30,34 @@ func (p *Spotidown) BaseURL() string {
 	return "https://spotidown.app"
 }
 
+func (p *Spotidown) Capabilities() capability.Capabilities {
+	return capability.Capabilities{
+		Kinds:     []string{capability.Track},
+		AudioOnly: true,
+	}
+}
+
 func (p *Spotidown) Stream(spotifyURL string) (string, error) {
 	if spotifyURL == "" {
 		return "", errors.New("url cannot be empty")
 	}
 
-	jar, _ := cookiejar.New(nil)
-	if p.Client == nil {
-		p.Client = &http.Client{Jar: jar}
-	} else {
-		p.Client.Jar = jar
-	}
+	// The handshake cookies belong to this call only, so it runs on a copy
+	// of the provider with a fresh jar and p.Client is never touched.
+	session := *p
+	session.Client = cookies.Client(p.Client, cookies.New(""))
 
-	tokenFieldName, tokenValue, err := p.GetInitialTokens()
+	tokenFieldName, tokenValue, err := session.GetInitialTokens()
 	if err != nil {
 		return "", err
 	}
 
-	formData, err := p.GetFormData(spotifyURL, tokenFieldName, tokenValue)
+	formData, err := session.GetFormData(spotifyURL, tokenFieldName, tokenValue)
 	if err != nil {
 		return "", err
 	}
 
-	rawURL, err := p.GetRawDownloadLink(formData)
+	rawURL, err := session.GetRawDownloadLink(formData)
 	if err != nil {
 		return "", err
 	}
@@ -222,5 +233,8 @@ func (p *Spotidown) GetRawDownloadLink(formData map[string]string) (string, erro
 }
 
 func (p *Spotidown) ProxyURL(rawURL string) string {
-	return "https://proxy-xi-dun.vercel.app/proxy_audio?url=" + rawURL
+	if p.ProxyLink == nil {
+		return rawURL
+	}
+	return p.ProxyLink(rawURL)
 }
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/Beesonn/dlkitgo/capability"
 	"github.com/PuerkitoBio/goquery"
 )
 
@@ -23,6 +24,13 @@ func (p *SpotMate) BaseURL() string {
 	return "https://spotmate.online"
 }
 
+func (p *SpotMate) Capabilities() capability.Capabilities {
+	return capability.Capabilities{
+		Kinds:     []string{capability.Track},
+		AudioOnly: true,
+	}
+}
+
 func (p *SpotMate) Stream(spotifyURL string) (string, error) {
 	if spotifyURL == "" {
 		return "", errors.New("cannot be empty")
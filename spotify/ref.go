@@ -0,0 +1,122 @@
+package spotify
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/Beesonn/dlkitgo/errkind"
+)
+
+type SpotifyRef struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+var (
+	SpotifyTypes    = []string{"track", "album", "playlist", "artist", "show", "episode"}
+	SpotifyIDRegex  = regexp.MustCompile(`^[0-9A-Za-z]{22}$`)
+	SpotifyURIRegex = regexp.MustCompile(`^spotify:(track|album|playlist|artist|show|episode):([0-9A-Za-z]+)$`)
+	SpotifyURLRegex = regexp.MustCompile(`^(?:https?://)?(?:open|play)\.spotify\.com/(?:intl-[a-zA-Z_-]+/)?(?:embed/)?(track|album|playlist|artist|show|episode)/([0-9A-Za-z]+)`)
+	ShortLinkRegex  = regexp.MustCompile(`^(?:https?://)?(?:spotify\.link|spotify\.app\.link)/[0-9A-Za-z_-]+`)
+	ShortLinkTarget = regexp.MustCompile(`https://open\.spotify\.com/(?:intl-[a-zA-Z_-]+/)?(?:track|album|playlist|artist|show|episode)/[0-9A-Za-z]+`)
+)
+
+func (r SpotifyRef) URL() string {
+	return fmt.Sprintf("https://open.spotify.com/%s/%s", r.Type, r.ID)
+}
+
+func (r SpotifyRef) URI() string {
+	return fmt.Sprintf("spotify:%s:%s", r.Type, r.ID)
+}
+
+// ParseRef normalizes an open.spotify.com URL, a spotify: URI or a bare ID
+// into a SpotifyRef. Bare IDs are read as typeHint, or as tracks without one.
+// Short links need a network round trip, see SpotifyService.ResolveRef.
+func ParseRef(input string, typeHint ...string) (SpotifyRef, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return SpotifyRef{}, errors.New("url or id cannot be empty")
+	}
+
+	if m := SpotifyURIRegex.FindStringSubmatch(input); m != nil {
+		return SpotifyRef{Type: m[1], ID: m[2]}, nil
+	}
+
+	if m := SpotifyURLRegex.FindStringSubmatch(input); m != nil {
+		return SpotifyRef{Type: m[1], ID: m[2]}, nil
+	}
+
+	if SpotifyIDRegex.MatchString(input) {
+		typ := "track"
+		if len(typeHint) > 0 && typeHint[0] != "" {
+			typ = strings.ToLower(typeHint[0])
+		}
+		for _, t := range SpotifyTypes {
+			if t == typ {
+				return SpotifyRef{Type: typ, ID: input}, nil
+			}
+		}
+		return SpotifyRef{}, fmt.Errorf("unsupported type hint: %s", typ)
+	}
+
+	if ShortLinkRegex.MatchString(input) {
+		return SpotifyRef{}, errors.New("short links must be resolved with ResolveRef")
+	}
+
+	return SpotifyRef{}, errkind.New(errkind.ErrInvalidURL, "Invalid URL or ID")
+}
+
+// ResolveRef is ParseRef with support for spotify.link short links.
+func (s *SpotifyService) ResolveRef(input string, typeHint ...string) (SpotifyRef, error) {
+	input = strings.TrimSpace(input)
+	if !ShortLinkRegex.MatchString(input) {
+		return ParseRef(input, typeHint...)
+	}
+
+	target, err := s.ExpandShortLink(input)
+	if err != nil {
+		return SpotifyRef{}, err
+	}
+	return ParseRef(target)
+}
+
+func (s *SpotifyService) ExpandShortLink(link string) (string, error) {
+	if !strings.HasPrefix(link, "http") {
+		link = "https://" + link
+	}
+	if _, err := url.Parse(link); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest("GET", link, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve short link: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if final := resp.Request.URL.String(); SpotifyURLRegex.MatchString(final) {
+		return final, nil
+	}
+
+	// spotify.link answers with an interstitial page that redirects in JS.
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	if target := ShortLinkTarget.FindString(string(body)); target != "" {
+		return target, nil
+	}
+
+	return "", errkind.New(errkind.ErrInvalidURL, "could not resolve short link")
+}
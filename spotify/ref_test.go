@@ -0,0 +1,67 @@
+package spotify
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Beesonn/dlkitgo/errkind"
+)
+
+func TestParseRef(t *testing.T) {
+	const id = "4uLU6hMCjMI75M1A2tKUQC"
+
+	for _, tc := range []struct {
+		input   string
+		hint    string
+		want    SpotifyRef
+		wantErr bool
+	}{
+		{input: "https://open.spotify.com/track/" + id, want: SpotifyRef{"track", id}},
+		{input: "https://open.spotify.com/intl-de/album/" + id + "?si=abc", want: SpotifyRef{"album", id}},
+		{input: "open.spotify.com/embed/playlist/" + id, want: SpotifyRef{"playlist", id}},
+		{input: "https://play.spotify.com/artist/" + id, want: SpotifyRef{"artist", id}},
+		{input: "https://open.spotify.com/show/" + id, want: SpotifyRef{"show", id}},
+		{input: "spotify:episode:" + id, want: SpotifyRef{"episode", id}},
+		{input: "  " + id + "  ", want: SpotifyRef{"track", id}},
+		{input: id, hint: "Album", want: SpotifyRef{"album", id}},
+		{input: id, hint: "podcast", wantErr: true},
+		{input: "https://spotify.link/abcDEF", wantErr: true},
+		{input: "https://open.spotify.com/user/someone", wantErr: true},
+		{input: "", wantErr: true},
+	} {
+		got, err := ParseRef(tc.input, tc.hint)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("ParseRef(%q, %q) = %v, want an error", tc.input, tc.hint, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseRef(%q, %q): %v", tc.input, tc.hint, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("ParseRef(%q, %q) = %v, want %v", tc.input, tc.hint, got, tc.want)
+		}
+	}
+}
+
+func TestParseRefInvalidURL(t *testing.T) {
+	_, err := ParseRef("https://example.com/track/1")
+	if !errors.Is(err, errkind.ErrInvalidURL) {
+		t.Errorf("err = %v, want ErrInvalidURL", err)
+	}
+}
+
+func TestSpotifyRefURLs(t *testing.T) {
+	ref := SpotifyRef{Type: "album", ID: "abc"}
+	if got := ref.URL(); got != "https://open.spotify.com/album/abc" {
+		t.Errorf("URL() = %q", got)
+	}
+	if got := ref.URI(); got != "spotify:album:abc" {
+		t.Errorf("URI() = %q", got)
+	}
+	if back, err := ParseRef(ref.URI()); err != nil || back != ref {
+		t.Errorf("ParseRef(URI()) = %v, %v", back, err)
+	}
+}
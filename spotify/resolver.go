@@ -0,0 +1,330 @@
+package spotify
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Beesonn/dlkitgo/capability"
+	"github.com/Beesonn/dlkitgo/youtube"
+	ytproviders "github.com/Beesonn/dlkitgo/youtube/providers"
+)
+
+// TrackProvider is implemented by providers that resolve a track from its
+// metadata instead of its Spotify URL.
+type TrackProvider interface {
+	StreamTrack(track TrackInfo) (YouTubeMatch, error)
+}
+
+type YouTubeMatch struct {
+	ID        string  `json:"id"`
+	URL       string  `json:"url"`
+	Title     string  `json:"title"`
+	Channel   string  `json:"channel"`
+	Duration  int     `json:"duration"`
+	Score     float64 `json:"score"`
+	StreamURL string  `json:"stream_url"`
+	Quality   string  `json:"quality"`
+}
+
+type YouTubeResolver struct {
+	Client *http.Client
+	Tube   *youtube.TubeService
+	// Info looks up the track behind a Spotify URL for Stream. NewSpotify
+	// points it at the service's own GetInfo, so the service's client,
+	// cookies and host limits apply.
+	Info func(url string, typeHint ...string) (SpotifyData, error)
+
+	// Candidates scoring below MinScore are rejected.
+	MinScore float64
+	// Candidates whose length differs by more than MaxDurationDelta seconds
+	// are rejected. Zero disables the check.
+	MaxDurationDelta int
+	SearchLimit      int
+
+	TitleWeight    float64
+	ArtistWeight   float64
+	DurationWeight float64
+}
+
+var (
+	resolverBracketRegex = regexp.MustCompile(`[\(\[][^\)\]]*[\)\]]`)
+	resolverWordRegex    = regexp.MustCompile(`[\p{L}\p{N}]+`)
+	resolverNoiseWords   = []string{"live", "cover", "remix", "karaoke", "instrumental", "sped", "slowed", "reverb", "nightcore", "8d"}
+)
+
+func NewYouTubeResolver(client *http.Client) *YouTubeResolver {
+	return &YouTubeResolver{
+		Client:           client,
+		Tube:             youtube.NewTube(client),
+		MinScore:         0.6,
+		MaxDurationDelta: 20,
+		SearchLimit:      8,
+		TitleWeight:      0.5,
+		ArtistWeight:     0.3,
+		DurationWeight:   0.2,
+	}
+}
+
+func (r *YouTubeResolver) Name() string {
+	return "youtube"
+}
+
+func (r *YouTubeResolver) BaseURL() string {
+	return "https://www.youtube.com"
+}
+
+func (r *YouTubeResolver) Capabilities() capability.Capabilities {
+	return capability.Capabilities{
+		Kinds:     []string{capability.Track},
+		AudioOnly: true,
+	}
+}
+
+func (r *YouTubeResolver) Stream(spotifyURL string) (string, error) {
+	if spotifyURL == "" {
+		return "", errors.New("url cannot be empty")
+	}
+
+	if r.Info == nil {
+		return "", errors.New("no Spotify lookup configured to resolve the URL")
+	}
+	info, err := r.Info(spotifyURL)
+	if err != nil {
+		return "", err
+	}
+
+	track := TrackInfo{Name: info.Name, Artist: info.Artist, Duration: info.Duration}
+	if len(info.Tracks) > 0 {
+		track = info.Tracks[0]
+	}
+
+	match, err := r.StreamTrack(track)
+	if err != nil {
+		return "", err
+	}
+
+	return match.StreamURL, nil
+}
+
+func (r *YouTubeResolver) StreamTrack(track TrackInfo) (YouTubeMatch, error) {
+	candidates, err := r.Match(track)
+	if err != nil {
+		return YouTubeMatch{}, err
+	}
+
+	for _, match := range candidates {
+		res, err := r.Tube.Stream(match.URL)
+		if err != nil {
+			continue
+		}
+
+		source, ok := r.BestAudioSource(res.Source)
+		if !ok {
+			continue
+		}
+
+		match.StreamURL = source.URL
+		match.Quality = source.Quality
+		return match, nil
+	}
+
+	return YouTubeMatch{}, errors.New("no matching YouTube video could be streamed")
+}
+
+// Match searches YouTube for the track and returns the candidates that pass
+// the configured thresholds, best first.
+func (r *YouTubeResolver) Match(track TrackInfo) ([]YouTubeMatch, error) {
+	if track.Name == "" {
+		return nil, errors.New("track name cannot be empty")
+	}
+
+	if r.Tube == nil {
+		r.Tube = youtube.NewTube(r.Client)
+	}
+
+	query := track.Name
+	if track.Artist != "" {
+		query = track.Artist + " - " + track.Name
+	}
+
+	limit := r.SearchLimit
+	if limit <= 0 {
+		limit = 8
+	}
+
+	search, err := r.Tube.Search(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("youtube search failed: %w", err)
+	}
+
+	var matches []YouTubeMatch
+	for _, result := range search.Results {
+		if r.MaxDurationDelta > 0 && track.Duration > 0 && result.Duration > 0 {
+			if absInt(track.Duration-result.Duration) > r.MaxDurationDelta {
+				continue
+			}
+		}
+
+		score := r.Score(track, result)
+		if score < r.MinScore {
+			continue
+		}
+
+		matches = append(matches, YouTubeMatch{
+			ID:       result.ID,
+			URL:      result.URL,
+			Title:    result.Name,
+			Channel:  result.Channel,
+			Duration: result.Duration,
+			Score:    score,
+		})
+	}
+
+	if len(matches) == 0 {
+		return nil, errors.New("no YouTube video matched the track")
+	}
+
+	for i := 1; i < len(matches); i++ {
+		for j := i; j > 0 && matches[j].Score > matches[j-1].Score; j-- {
+			matches[j], matches[j-1] = matches[j-1], matches[j]
+		}
+	}
+
+	return matches, nil
+}
+
+// Score rates how well a YouTube search result matches the track, from 0 to 1.
+func (r *YouTubeResolver) Score(track TrackInfo, result youtube.SearchResult) float64 {
+	titleWeight, artistWeight, durationWeight := r.TitleWeight, r.ArtistWeight, r.DurationWeight
+	total := titleWeight + artistWeight + durationWeight
+	if total <= 0 {
+		titleWeight, artistWeight, durationWeight, total = 0.5, 0.3, 0.2, 1
+	}
+
+	score := titleWeight*r.TitleScore(track.Name, result.Name) +
+		artistWeight*r.ArtistScore(track.Artist, result.Name+" "+result.Channel) +
+		durationWeight*r.DurationScore(track.Duration, result.Duration)
+
+	return math.Round(score/total*1000) / 1000
+}
+
+func (r *YouTubeResolver) TitleScore(name, title string) float64 {
+	want := resolverTokens(resolverBracketRegex.ReplaceAllString(name, " "))
+	if len(want) == 0 {
+		want = resolverTokens(name)
+	}
+	if len(want) == 0 {
+		return 0
+	}
+
+	have := map[string]bool{}
+	for _, token := range resolverTokens(title) {
+		have[token] = true
+	}
+
+	found := 0
+	for _, token := range want {
+		if have[token] {
+			found++
+		}
+	}
+	score := float64(found) / float64(len(want))
+
+	wanted := map[string]bool{}
+	for _, token := range resolverTokens(name) {
+		wanted[token] = true
+	}
+	for _, noise := range resolverNoiseWords {
+		if have[noise] && !wanted[noise] {
+			score -= 0.25
+		}
+	}
+
+	return math.Max(score, 0)
+}
+
+func (r *YouTubeResolver) ArtistScore(artist, text string) float64 {
+	var artists []string
+	for _, a := range strings.Split(artist, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			artists = append(artists, a)
+		}
+	}
+	if len(artists) == 0 {
+		return 0
+	}
+
+	have := map[string]bool{}
+	for _, token := range resolverTokens(text) {
+		have[token] = true
+	}
+
+	found := 0
+	for _, a := range artists {
+		tokens := resolverTokens(a)
+		matched := len(tokens) > 0
+		for _, token := range tokens {
+			if !have[token] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			found++
+		}
+	}
+
+	if found == 0 {
+		return 0
+	}
+	// The main artist is usually enough for a confident match.
+	return math.Min(1, 0.7+0.3*float64(found)/float64(len(artists)))
+}
+
+func (r *YouTubeResolver) DurationScore(want, have int) float64 {
+	if want <= 0 || have <= 0 {
+		return 0.5
+	}
+
+	window := r.MaxDurationDelta
+	if window <= 0 {
+		window = 20
+	}
+
+	delta := absInt(want - have)
+	if delta >= window {
+		return 0
+	}
+	return 1 - float64(delta)/float64(window)
+}
+
+func (r *YouTubeResolver) BestAudioSource(sources []ytproviders.YTSource) (ytproviders.YTSource, bool) {
+	var best ytproviders.YTSource
+	bestRate := -1
+	for _, source := range sources {
+		if source.Type != "audio" || source.URL == "" {
+			continue
+		}
+		rate, _ := strconv.Atoi(strings.TrimSuffix(strings.ToLower(source.Quality), "kbps"))
+		if rate > bestRate {
+			best, bestRate = source, rate
+		}
+	}
+	return best, bestRate >= 0
+}
+
+func resolverTokens(text string) []string {
+	return resolverWordRegex.FindAllString(strings.ToLower(text), -1)
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
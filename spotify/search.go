@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 )
 
 type SearchResult struct {
@@ -22,33 +24,290 @@ type SearchResult struct {
 	PreviewURL  string `json:"preview_url,omitempty"`
 }
 
+// SearchResponse is one page of results. TotalResults counts the matches
+// Spotify has for all requested types together, so callers can page
+// through them with Offset and Limit.
 type SearchResponse struct {
 	Query        string         `json:"query"`
 	Type         string         `json:"type"`
 	Limit        int            `json:"limit"`
+	Offset       int            `json:"offset"`
+	Market       string         `json:"market,omitempty"`
 	TotalResults int            `json:"total_results"`
 	Results      []SearchResult `json:"results"`
 }
 
+type SearchOptions struct {
+	// Type is one of track, album, artist, playlist, show or all. Several
+	// types may be combined with commas.
+	Type   string
+	Offset int
+	Limit  int
+	Market string
+}
+
+const DefaultSearchProxyURL = "https://dlkitlib-spot.vercel.app/api/search"
+
+var SearchTypes = []string{"track", "album", "artist", "playlist", "show"}
+
+type apiSearchPage[T any] struct {
+	Items []*T `json:"items"`
+	Total int  `json:"total"`
+}
+
+type apiSearchAlbum struct {
+	apiAlbum
+	AlbumType string `json:"album_type"`
+}
+
+type apiSearchPlaylist struct {
+	ID     string     `json:"id"`
+	Name   string     `json:"name"`
+	Images []apiImage `json:"images"`
+	Owner  struct {
+		DisplayName string `json:"display_name"`
+	} `json:"owner"`
+}
+
+type apiSearchNamed struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Publisher string     `json:"publisher"`
+	Images    []apiImage `json:"images"`
+}
+
+type apiSearchResponse struct {
+	Tracks    *apiSearchPage[apiTrack]          `json:"tracks"`
+	Albums    *apiSearchPage[apiSearchAlbum]    `json:"albums"`
+	Artists   *apiSearchPage[apiSearchNamed]    `json:"artists"`
+	Playlists *apiSearchPage[apiSearchPlaylist] `json:"playlists"`
+	Shows     *apiSearchPage[apiSearchNamed]    `json:"shows"`
+}
+
 func (s *SpotifyService) Search(query string, searchType ...string) (*SearchResponse, error) {
+	opts := SearchOptions{}
+	if len(searchType) > 0 {
+		opts.Type = searchType[0]
+	}
+	return s.SearchWithOptions(query, opts)
+}
+
+// SearchWithOptions searches Spotify's web API directly and falls back to
+// SearchProxyURL, when one is set, if that fails.
+func (s *SpotifyService) SearchWithOptions(query string, opts SearchOptions) (*SearchResponse, error) {
 	if query == "" {
 		return nil, errors.New("query cannot be empty")
 	}
-	sType := "all"
-	if len(searchType) > 0 && searchType[0] != "" {
-		sType = searchType[0]
+
+	types, err := s.ParseSearchTypes(opts.Type)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = 20
+	}
+	if opts.Limit > 50 {
+		opts.Limit = 50
+	}
+	if opts.Offset < 0 {
+		opts.Offset = 0
+	}
+
+	results, err := s.SearchAPI(query, types, opts)
+	if err == nil {
+		return results, nil
+	}
+
+	if s.SearchProxyURL == "" {
+		return nil, err
+	}
+
+	proxyResults, proxyErr := s.SearchProxy(query, opts)
+	if proxyErr != nil {
+		return nil, fmt.Errorf("search failed: %v; proxy fallback: %w", err, proxyErr)
+	}
+	return proxyResults, nil
+}
+
+func (s *SpotifyService) ParseSearchTypes(searchType string) ([]string, error) {
+	searchType = strings.ToLower(strings.TrimSpace(searchType))
+	if searchType == "" || searchType == "all" {
+		return SearchTypes, nil
+	}
+
+	var types []string
+	for _, t := range strings.Split(searchType, ",") {
+		t = strings.TrimSpace(t)
+		valid := false
+		for _, known := range SearchTypes {
+			if t == known {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("unsupported search type: %s", t)
+		}
+		types = append(types, t)
+	}
+	return types, nil
+}
+
+func (s *SpotifyService) SearchAPI(query string, types []string, opts SearchOptions) (*SearchResponse, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("type", strings.Join(types, ","))
+	params.Set("limit", strconv.Itoa(opts.Limit))
+	params.Set("offset", strconv.Itoa(opts.Offset))
+	if opts.Market != "" {
+		params.Set("market", opts.Market)
+	}
+
+	var resp apiSearchResponse
+	if err := s.APIGet("/search", params, &resp); err != nil {
+		return nil, err
+	}
+
+	results := &SearchResponse{
+		Query:   query,
+		Type:    strings.Join(types, ","),
+		Limit:   opts.Limit,
+		Offset:  opts.Offset,
+		Market:  opts.Market,
+		Results: []SearchResult{},
+	}
+
+	if resp.Tracks != nil {
+		for _, t := range resp.Tracks.Items {
+			if t == nil || t.ID == "" {
+				continue
+			}
+			track := s.TrackFromAPI(t, &SpotifyData{})
+			results.Results = append(results.Results, SearchResult{
+				ID:          t.ID,
+				URL:         track.URL,
+				Image:       track.Image,
+				Duration:    track.Duration,
+				Artists:     track.Artist,
+				Type:        "track",
+				Name:        track.Name,
+				Album:       track.Album,
+				ReleaseDate: track.ReleaseDate,
+				PreviewURL:  track.PreviewURL,
+			})
+		}
+	}
+
+	if resp.Albums != nil {
+		for _, a := range resp.Albums.Items {
+			if a == nil || a.ID == "" {
+				continue
+			}
+			results.Results = append(results.Results, SearchResult{
+				ID:          a.ID,
+				URL:         SpotifyRef{Type: "album", ID: a.ID}.URL(),
+				Image:       firstImage(a.Images),
+				Artists:     joinArtists(a.Artists),
+				Type:        "album",
+				Name:        a.Name,
+				ReleaseDate: a.ReleaseDate,
+			})
+		}
+	}
+
+	if resp.Artists != nil {
+		for _, a := range resp.Artists.Items {
+			if a == nil || a.ID == "" {
+				continue
+			}
+			results.Results = append(results.Results, SearchResult{
+				ID:      a.ID,
+				URL:     SpotifyRef{Type: "artist", ID: a.ID}.URL(),
+				Image:   firstImage(a.Images),
+				Artists: a.Name,
+				Type:    "artist",
+				Name:    a.Name,
+			})
+		}
+	}
+
+	if resp.Playlists != nil {
+		for _, p := range resp.Playlists.Items {
+			if p == nil || p.ID == "" {
+				continue
+			}
+			results.Results = append(results.Results, SearchResult{
+				ID:      p.ID,
+				URL:     SpotifyRef{Type: "playlist", ID: p.ID}.URL(),
+				Image:   firstImage(p.Images),
+				Artists: p.Owner.DisplayName,
+				Type:    "playlist",
+				Name:    p.Name,
+			})
+		}
+	}
+
+	if resp.Shows != nil {
+		for _, sh := range resp.Shows.Items {
+			if sh == nil || sh.ID == "" {
+				continue
+			}
+			results.Results = append(results.Results, SearchResult{
+				ID:      sh.ID,
+				URL:     SpotifyRef{Type: "show", ID: sh.ID}.URL(),
+				Image:   firstImage(sh.Images),
+				Artists: sh.Publisher,
+				Type:    "show",
+				Name:    sh.Name,
+			})
+		}
+	}
+
+	for _, total := range []int{
+		pageTotal(resp.Tracks),
+		pageTotal(resp.Albums),
+		pageTotal(resp.Artists),
+		pageTotal(resp.Playlists),
+		pageTotal(resp.Shows),
+	} {
+		results.TotalResults += total
+	}
+	return results, nil
+}
+
+func pageTotal[T any](page *apiSearchPage[T]) int {
+	if page == nil {
+		return 0
+	}
+	return page.Total
+}
+
+// SearchProxy searches through SearchProxyURL. Offset, Limit and Market are
+// passed on, and the response reports what the proxy actually applied.
+func (s *SpotifyService) SearchProxy(query string, opts SearchOptions) (*SearchResponse, error) {
+	searchType := opts.Type
+	if searchType == "" {
+		searchType = "all"
 	}
 
-	api := "https://dlkitlib-spot.vercel.app/api/search"
 	queryParams := url.Values{}
 	queryParams.Add("q", query)
-	queryParams.Add("type", sType)
+	queryParams.Add("type", searchType)
+	if opts.Limit > 0 {
+		queryParams.Add("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Offset > 0 {
+		queryParams.Add("offset", strconv.Itoa(opts.Offset))
+	}
+	if opts.Market != "" {
+		queryParams.Add("market", opts.Market)
+	}
 
-	fullURL := api + "?" + queryParams.Encode()
+	fullURL := s.SearchProxyURL + "?" + queryParams.Encode()
 
 	resp, err := s.Client.Get(fullURL)
 	if err != nil {
-		return nil, errors.New("Something went wrong!")
+		return nil, fmt.Errorf("proxy request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -68,3 +327,18 @@ func (s *SpotifyService) Search(query string, searchType ...string) (*SearchResp
 
 	return &results, nil
 }
+
+func firstImage(images []apiImage) string {
+	if len(images) > 0 {
+		return images[0].URL
+	}
+	return ""
+}
+
+func joinArtists(artists []apiArtist) string {
+	names := make([]string, 0, len(artists))
+	for _, a := range artists {
+		names = append(names, a.Name)
+	}
+	return strings.Join(names, ", ")
+}
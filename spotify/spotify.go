@@ -4,15 +4,36 @@ import (
 	"errors"
 	"net/http"
 	"sync"
+	"time"
+
+	"github.com/Beesonn/dlkitgo/capability"
+	"github.com/Beesonn/dlkitgo/cookies"
+	"github.com/Beesonn/dlkitgo/errkind"
 )
 
 type TrackSource struct {
-	Title       string `json:"title"`
-	Artist      string `json:"artist"`
-	Image       string `json:"image"`
-	URL         string `json:"url"`
-	Duration    int    `json:"duration"`
-	ReleaseDate string `json:"release_date"`
+	SpotifyID   string  `json:"spotify_id,omitempty"`
+	Title       string  `json:"title"`
+	Artist      string  `json:"artist"`
+	Image       string  `json:"image"`
+	URL         string  `json:"url"`
+	Duration    int     `json:"duration"`
+	ReleaseDate string  `json:"release_date"`
+	Album       string  `json:"album,omitempty"`
+	AlbumArtist string  `json:"album_artist,omitempty"`
+	DiscNumber  int     `json:"disc_number,omitempty"`
+	TrackNumber int     `json:"track_number,omitempty"`
+	ISRC        string  `json:"isrc,omitempty"`
+	Explicit    bool    `json:"explicit,omitempty"`
+	MatchScore  float64 `json:"match_score,omitempty"`
+}
+
+type FailedTrack struct {
+	Index  int    `json:"index"`
+	Title  string `json:"title"`
+	Artist string `json:"artist"`
+	URL    string `json:"url"`
+	Error  string `json:"error"`
 }
 
 type StreamResult struct {
@@ -20,26 +41,66 @@ type StreamResult struct {
 	ID     string        `json:"id"`
 	Type   string        `json:"type"`
 	Source []TrackSource `json:"source"`
+	Failed []FailedTrack `json:"failed,omitempty"`
 }
 
 type SpotifyService struct {
 	Client    *http.Client
 	Providers []Provider
+
+	// Workers bounds how many tracks are processed at once and HostLimit how
+	// many requests may be in flight to a single host.
+	Workers   int
+	HostLimit int
+	limiter   hostLimiter
+
+	// MaxEpisodes caps how many of a show's episodes, newest first, GetInfo
+	// lists and Stream looks up. Zero means DefaultMaxEpisodes and a
+	// negative value lists every episode. GetShowEpisodes pages further.
+	MaxEpisodes int
+
+	// SearchProxyURL is the search backend used when the web API fails.
+	// Leave it empty to disable the fallback.
+	SearchProxyURL string
+
+	tokenMu     sync.Mutex
+	token       string
+	tokenExpiry time.Time
 }
 
 func NewSpotify(client *http.Client) *SpotifyService {
-	return &SpotifyService{
+	s := &SpotifyService{
 		Client:    client,
 		Providers: DefaultProviders(client),
+		Workers:   DefaultWorkers,
+		HostLimit: DefaultHostLimit,
+
+		MaxEpisodes: DefaultMaxEpisodes,
+
+		SearchProxyURL: DefaultSearchProxyURL,
+	}
+
+	for _, provider := range s.Providers {
+		if r, ok := provider.(*YouTubeResolver); ok {
+			r.Info = s.GetInfo
+		}
 	}
+	return s
+}
+
+// UseCookies sends the requests the service makes to Spotify itself, such
+// as metadata and token lookups, with the cookies in jar. Providers keep
+// the client they were built with.
+func (s *SpotifyService) UseCookies(jar http.CookieJar) {
+	s.Client = cookies.Client(s.Client, jar)
 }
 
-func (s *SpotifyService) Stream(url string) (StreamResult, error) {
+func (s *SpotifyService) Stream(url string, typeHint ...string) (StreamResult, error) {
 	if url == "" {
 		return StreamResult{}, errors.New("url or id cannot be empty")
 	}
 
-	info, err := s.GetInfo(url)
+	info, err := s.GetInfo(url, typeHint...)
 	if err != nil {
 		return StreamResult{}, err
 	}
@@ -50,6 +111,17 @@ func (s *SpotifyService) Stream(url string) (StreamResult, error) {
 		Type: info.Type,
 	}
 
+	if info.Type == "show" || info.Type == "episode" {
+		if info.Type == "show" {
+			if limit := s.maxEpisodes(); limit > 0 && len(info.Episodes) > limit {
+				info.Episodes = info.Episodes[:limit]
+			}
+			s.FetchEpisodeAudio(&info)
+		}
+		result.Source, result.Failed = s.EpisodeSources(info)
+		return result, nil
+	}
+
 	var tracks []TrackInfo
 	if info.Type == "track" && len(info.Tracks) > 0 {
 		tracks = info.Tracks
@@ -61,41 +133,77 @@ func (s *SpotifyService) Stream(url string) (StreamResult, error) {
 		tracks = []TrackInfo{{Name: info.Name, Artist: info.Artist}}
 	}
 
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	sources := make([]TrackSource, 0, len(tracks))
-
-	for _, track := range tracks {
-		wg.Add(1)
-		go func(t TrackInfo) {
-			defer wg.Done()
-
-			var streamURL string
-			for _, provider := range s.Providers {
-				u, err := provider.Stream(t.URL)
-				if err == nil && u != "" {
-					streamURL = u
-					break
-				}
-			}
+	sources := make([]TrackSource, len(tracks))
+	errs := make([]error, len(tracks))
+	s.ForEach(len(tracks), func(i int) {
+		sources[i], errs[i] = s.StreamTrack(tracks[i])
+	})
+
+	result.Source = make([]TrackSource, 0, len(tracks))
+	for i, t := range tracks {
+		if errs[i] != nil {
+			result.Failed = append(result.Failed, FailedTrack{
+				Index:  i,
+				Title:  t.Name,
+				Artist: t.Artist,
+				URL:    t.URL,
+				Error:  errs[i].Error(),
+			})
+			continue
+		}
+		result.Source = append(result.Source, sources[i])
+	}
 
-			if streamURL != "" {
-				mu.Lock()
-				sources = append(sources, TrackSource{
-					Title:       t.Name,
-					Artist:      t.Artist,
-					Image:       t.Image,
-					URL:         streamURL,
-					ReleaseDate: t.ReleaseDate,
-					Duration:    t.Duration,
-				})
-				mu.Unlock()
-			}
-		}(track)
+	return result, nil
+}
+
+func (s *SpotifyService) StreamTrack(t TrackInfo) (TrackSource, error) {
+	source := TrackSource{
+		SpotifyID:   t.SpotifyID,
+		Title:       t.Name,
+		Artist:      t.Artist,
+		Image:       t.Image,
+		ReleaseDate: t.ReleaseDate,
+		Duration:    t.Duration,
+		Album:       t.Album,
+		AlbumArtist: t.AlbumArtist,
+		DiscNumber:  t.DiscNumber,
+		TrackNumber: t.TrackNumber,
+		ISRC:        t.ISRC,
+		Explicit:    t.Explicit,
 	}
 
-	wg.Wait()
-	result.Source = sources
+	lastErr := errkind.New(errkind.ErrProvidersFailed, "no provider could stream the track")
+	for _, provider := range s.ProvidersFor(capability.Track) {
+		if tp, ok := provider.(TrackProvider); ok {
+			release := s.AcquireHost(provider.BaseURL())
+			match, err := tp.StreamTrack(t)
+			release()
+			if err == nil && match.StreamURL != "" {
+				source.URL = match.StreamURL
+				source.MatchScore = match.Score
+				return source, nil
+			}
+			if err != nil {
+				lastErr = errkind.New(errkind.ErrProvidersFailed, "%s: %w", provider.Name(), err)
+			}
+			continue
+		}
+
+		if t.URL == "" {
+			continue
+		}
+		release := s.AcquireHost(provider.BaseURL())
+		u, err := provider.Stream(t.URL)
+		release()
+		if err == nil && u != "" {
+			source.URL = u
+			return source, nil
+		}
+		if err != nil {
+			lastErr = errkind.New(errkind.ErrProvidersFailed, "%s: %w", provider.Name(), err)
+		}
+	}
 
-	return result, nil
+	return source, lastErr
 }
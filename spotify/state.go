@@ -0,0 +1,104 @@
+package spotify
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+func (s *SpotifyService) ParseInitialState(encoded string, data *SpotifyData) {
+	encoded = strings.TrimSpace(encoded)
+	if encoded == "" || data.SpotifyID == "" {
+		return
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		if raw, err = base64.RawStdEncoding.DecodeString(encoded); err != nil {
+			raw = []byte(encoded)
+		}
+	}
+
+	var state map[string]interface{}
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return
+	}
+
+	uri := fmt.Sprintf("spotify:%s:%s", data.Type, data.SpotifyID)
+	entity, _ := lookupMap(state, "entities", "items", uri)
+	if entity == nil {
+		return
+	}
+
+	switch data.Type {
+	case "artist":
+		s.ParseArtistOverview(entity, data)
+	case "show":
+		s.ParseShowOverview(entity, data)
+	case "episode":
+		s.ParseEpisodeOverview(entity, data)
+	}
+}
+
+func (s *SpotifyService) LargestImage(sources []interface{}) string {
+	var best string
+	bestWidth := -1.0
+	for _, src := range sources {
+		srcMap, ok := src.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		imgURL, _ := srcMap["url"].(string)
+		if imgURL == "" {
+			continue
+		}
+		width, _ := srcMap["width"].(float64)
+		if width > bestWidth {
+			best, bestWidth = imgURL, width
+		}
+	}
+	return best
+}
+
+func lookupMap(m map[string]interface{}, keys ...string) (map[string]interface{}, bool) {
+	current := m
+	for _, key := range keys {
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+	return current, true
+}
+
+func lookupValue(m map[string]interface{}, keys ...string) (interface{}, bool) {
+	if len(keys) == 0 {
+		return nil, false
+	}
+	parent, ok := lookupMap(m, keys[:len(keys)-1]...)
+	if !ok {
+		return nil, false
+	}
+	v, ok := parent[keys[len(keys)-1]]
+	return v, ok
+}
+
+func lookupString(m map[string]interface{}, keys ...string) (string, bool) {
+	v, _ := lookupValue(m, keys...)
+	str, ok := v.(string)
+	return str, ok
+}
+
+func lookupFloat(m map[string]interface{}, keys ...string) (float64, bool) {
+	v, _ := lookupValue(m, keys...)
+	f, ok := v.(float64)
+	return f, ok
+}
+
+func lookupSlice(m map[string]interface{}, keys ...string) ([]interface{}, bool) {
+	v, _ := lookupValue(m, keys...)
+	s, ok := v.([]interface{})
+	return s, ok
+}
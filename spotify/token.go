@@ -0,0 +1,109 @@
+package spotify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+var (
+	AccessTokenURL = "https://open.spotify.com/get_access_token?reason=transport&productType=web_player"
+	APIBaseURL     = "https://api.spotify.com/v1"
+)
+
+type accessTokenResponse struct {
+	ClientID    string `json:"clientId"`
+	AccessToken string `json:"accessToken"`
+	ExpiresAtMs int64  `json:"accessTokenExpirationTimestampMs"`
+	IsAnonymous bool   `json:"isAnonymous"`
+}
+
+// AccessToken returns an anonymous web-player token, reusing the cached one
+// until shortly before it expires.
+func (s *SpotifyService) AccessToken() (string, error) {
+	s.tokenMu.Lock()
+	defer s.tokenMu.Unlock()
+
+	if s.token != "" && time.Now().Before(s.tokenExpiry) {
+		return s.token, nil
+	}
+
+	req, err := http.NewRequest("GET", AccessTokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("access token error: %d", resp.StatusCode)
+	}
+
+	var token accessTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to parse access token: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", errors.New("empty access token")
+	}
+
+	s.token = token.AccessToken
+	s.tokenExpiry = time.UnixMilli(token.ExpiresAtMs).Add(-time.Minute)
+	if token.ExpiresAtMs == 0 {
+		s.tokenExpiry = time.Now().Add(30 * time.Minute)
+	}
+
+	return s.token, nil
+}
+
+// APIGet performs an authenticated GET against the public web API and
+// decodes the JSON response into v.
+func (s *SpotifyService) APIGet(path string, query url.Values, v interface{}) error {
+	token, err := s.AccessToken()
+	if err != nil {
+		return err
+	}
+
+	apiURL := APIBaseURL + path
+	if len(query) > 0 {
+		apiURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		s.tokenMu.Lock()
+		s.token = ""
+		s.tokenMu.Unlock()
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API error: %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	return nil
+}
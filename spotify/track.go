@@ -0,0 +1,173 @@
+package spotify
+
+import (
+	"fmt"
+	"strings"
+)
+
+type TrackArtist struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+func (s *SpotifyService) ExtractArtists(artists []interface{}) []TrackArtist {
+	var result []TrackArtist
+	for _, a := range artists {
+		artistMap, ok := a.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name, _ := artistMap["name"].(string)
+		if name == "" {
+			name, _ = lookupString(artistMap, "profile", "name")
+		}
+		if name == "" {
+			continue
+		}
+
+		artist := TrackArtist{Name: name}
+		artist.ID, _ = artistMap["id"].(string)
+		if uri, ok := artistMap["uri"].(string); ok && artist.ID == "" {
+			if parts := strings.Split(uri, ":"); len(parts) > 2 {
+				artist.ID = parts[len(parts)-1]
+			}
+		}
+		if artist.ID != "" {
+			artist.URL = fmt.Sprintf("https://open.spotify.com/artist/%s", artist.ID)
+		}
+		result = append(result, artist)
+	}
+	return result
+}
+
+// ExtractTrackDetails fills the fields of track that the embed and page
+// entities carry beyond name, artist and duration.
+func (s *SpotifyService) ExtractTrackDetails(trackMap map[string]interface{}, track *TrackInfo) {
+	if uri, ok := trackMap["uri"].(string); ok {
+		if parts := strings.Split(uri, ":"); len(parts) > 2 {
+			track.SpotifyID = parts[len(parts)-1]
+		}
+	}
+
+	if artists, ok := trackMap["artists"].([]interface{}); ok {
+		track.Artists = s.ExtractArtists(artists)
+	} else if artists, ok := lookupSlice(trackMap, "artists", "items"); ok {
+		track.Artists = s.ExtractArtists(artists)
+	}
+
+	if explicit, ok := trackMap["isExplicit"].(bool); ok {
+		track.Explicit = explicit
+	} else if label, ok := lookupString(trackMap, "contentRating", "label"); ok {
+		track.Explicit = label == "EXPLICIT"
+	}
+
+	if n, ok := trackMap["trackNumber"].(float64); ok {
+		track.TrackNumber = int(n)
+	}
+	if n, ok := trackMap["discNumber"].(float64); ok {
+		track.DiscNumber = int(n)
+	}
+
+	if album, ok := trackMap["album"].(map[string]interface{}); ok {
+		s.ExtractTrackAlbum(album, track)
+	} else if album, ok := trackMap["albumOfTrack"].(map[string]interface{}); ok {
+		s.ExtractTrackAlbum(album, track)
+	}
+}
+
+func (s *SpotifyService) ExtractTrackAlbum(album map[string]interface{}, track *TrackInfo) {
+	if name, ok := album["name"].(string); ok {
+		track.Album = name
+	}
+	if uri, ok := album["uri"].(string); ok {
+		if parts := strings.Split(uri, ":"); len(parts) > 2 {
+			track.AlbumURL = fmt.Sprintf("https://open.spotify.com/album/%s", parts[len(parts)-1])
+		}
+	}
+
+	var artists []TrackArtist
+	if list, ok := album["artists"].([]interface{}); ok {
+		artists = s.ExtractArtists(list)
+	} else if list, ok := lookupSlice(album, "artists", "items"); ok {
+		artists = s.ExtractArtists(list)
+	}
+	if len(artists) > 0 {
+		track.AlbumArtist = artists[0].Name
+	}
+}
+
+// FetchTrackDetails completes track with the ISRC, popularity, album and
+// numbering only the web API exposes. Failures leave track unchanged.
+func (s *SpotifyService) FetchTrackDetails(track *TrackInfo) error {
+	id := track.SpotifyID
+	if id == "" {
+		ref, err := ParseRef(track.URL)
+		if err != nil {
+			return err
+		}
+		id = ref.ID
+	}
+
+	var t apiTrack
+	if err := s.APIGet("/tracks/"+id, nil, &t); err != nil {
+		return err
+	}
+
+	full := s.TrackFromAPI(&t, &SpotifyData{})
+	s.MergeTrackInfo(track, full)
+	return nil
+}
+
+// MergeTrackInfo copies every field that is set in from into to.
+func (s *SpotifyService) MergeTrackInfo(to *TrackInfo, from TrackInfo) {
+	if from.Name != "" {
+		to.Name = from.Name
+	}
+	if from.Artist != "" {
+		to.Artist = from.Artist
+	}
+	if from.ReleaseDate != "" {
+		to.ReleaseDate = from.ReleaseDate
+	}
+	if from.Image != "" {
+		to.Image = from.Image
+	}
+	if from.PreviewURL != "" {
+		to.PreviewURL = from.PreviewURL
+	}
+	if from.Duration > 0 {
+		to.Duration = from.Duration
+	}
+	if from.SpotifyID != "" {
+		to.SpotifyID = from.SpotifyID
+	}
+	if len(from.Artists) > 0 {
+		to.Artists = from.Artists
+	}
+	if from.Album != "" {
+		to.Album = from.Album
+	}
+	if from.AlbumArtist != "" {
+		to.AlbumArtist = from.AlbumArtist
+	}
+	if from.AlbumURL != "" {
+		to.AlbumURL = from.AlbumURL
+	}
+	if from.DiscNumber > 0 {
+		to.DiscNumber = from.DiscNumber
+	}
+	if from.TrackNumber > 0 {
+		to.TrackNumber = from.TrackNumber
+	}
+	if from.ISRC != "" {
+		to.ISRC = from.ISRC
+	}
+	if from.Explicit {
+		to.Explicit = true
+	}
+	if from.Popularity > 0 {
+		to.Popularity = from.Popularity
+	}
+}
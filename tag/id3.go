@@ -0,0 +1,209 @@
+package tag
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type id3Frame struct {
+	ID   string
+	Body []byte
+}
+
+func tagMP3(data []byte, md Metadata) ([]byte, error) {
+	frames, audio, err := splitID3(data)
+	if err != nil {
+		return nil, err
+	}
+
+	frames = replaceID3Frames(frames, id3Frames(md)...)
+
+	var out bytes.Buffer
+	out.Write(buildID3(frames))
+	out.Write(audio)
+	return out.Bytes(), nil
+}
+
+func id3Frames(md Metadata) []id3Frame {
+	var frames []id3Frame
+	text := func(id, value string) {
+		if value != "" {
+			frames = append(frames, id3Frame{ID: id, Body: append([]byte{3}, value...)})
+		}
+	}
+
+	text("TIT2", md.Title)
+	text("TPE1", md.Artist)
+	text("TALB", md.Album)
+	text("TPE2", md.AlbumArtist)
+	text("TDRC", md.Date)
+	text("TSRC", md.ISRC)
+	text("TRCK", numberPair(md.TrackNumber, md.TrackTotal))
+	text("TPOS", numberPair(md.DiscNumber, md.DiscTotal))
+
+	if len(md.Cover) > 0 {
+		var body bytes.Buffer
+		body.WriteByte(3)
+		body.WriteString(md.CoverMIME)
+		body.WriteByte(0)
+		body.WriteByte(3) // front cover
+		body.WriteByte(0) // empty description
+		body.Write(md.Cover)
+		frames = append(frames, id3Frame{ID: "APIC", Body: body.Bytes()})
+	}
+
+	if md.Lyrics != "" {
+		var body bytes.Buffer
+		body.WriteByte(3)
+		body.WriteString(language(md.Language))
+		body.WriteByte(0)
+		body.WriteString(md.Lyrics)
+		frames = append(frames, id3Frame{ID: "USLT", Body: body.Bytes()})
+	}
+
+	if len(md.SyncedLyrics) > 0 {
+		var body bytes.Buffer
+		body.WriteByte(3)
+		body.WriteString(language(md.Language))
+		body.WriteByte(2) // timestamps in milliseconds
+		body.WriteByte(1) // content type: lyrics
+		body.WriteByte(0)
+		for _, line := range md.SyncedLyrics {
+			body.WriteString(line.Text)
+			body.WriteByte(0)
+			binary.Write(&body, binary.BigEndian, uint32(line.Time.Milliseconds()))
+		}
+		frames = append(frames, id3Frame{ID: "SYLT", Body: body.Bytes()})
+	}
+
+	return frames
+}
+
+// splitID3 separates an ID3v2.3/2.4 tag into its frames and returns the
+// audio that follows it. Files without a tag yield no frames.
+func splitID3(data []byte) ([]id3Frame, []byte, error) {
+	if len(data) < 10 || string(data[:3]) != "ID3" {
+		return nil, data, nil
+	}
+
+	version := data[3]
+	flags := data[5]
+	if version != 3 && version != 4 {
+		return nil, nil, errors.New("only ID3v2.3 and ID3v2.4 tags are supported")
+	}
+	if flags&0x80 != 0 {
+		return nil, nil, errors.New("unsynchronised ID3 tags are not supported")
+	}
+
+	end := 10 + syncsafe(data[6:10])
+	if flags&0x10 != 0 {
+		end += 10
+	}
+	if end > len(data) {
+		return nil, nil, errors.New("truncated ID3 tag")
+	}
+
+	pos := 10
+	if flags&0x40 != 0 {
+		if version == 4 {
+			pos += syncsafe(data[10:14])
+		} else {
+			pos += 4 + int(binary.BigEndian.Uint32(data[10:14]))
+		}
+	}
+
+	var frames []id3Frame
+	for pos+10 <= end && data[pos] != 0 {
+		id := string(data[pos : pos+4])
+		size := int(binary.BigEndian.Uint32(data[pos+4 : pos+8]))
+		if version == 4 {
+			size = syncsafe(data[pos+4 : pos+8])
+		}
+		formatFlags := data[pos+9]
+		bodyStart := pos + 10
+		if bodyStart+size > end {
+			break
+		}
+		// Compressed, encrypted or unsynchronised frames cannot be carried
+		// over as-is, so they are dropped.
+		if formatFlags == 0 {
+			frames = append(frames, id3Frame{ID: id, Body: data[bodyStart : bodyStart+size]})
+		}
+		pos = bodyStart + size
+	}
+
+	// v2.3 has TYER instead of TDRC; keep only one date.
+	if version == 3 {
+		for i, f := range frames {
+			if f.ID == "TYER" {
+				frames[i].ID = "TDRC"
+			}
+		}
+	}
+
+	return frames, data[end:], nil
+}
+
+// buildID3 renders frames as an ID3v2.4 tag with some padding for later edits.
+func buildID3(frames []id3Frame) []byte {
+	var body bytes.Buffer
+	for _, f := range frames {
+		body.WriteString(f.ID)
+		body.Write(syncsafeBytes(len(f.Body)))
+		body.Write([]byte{0, 0})
+		body.Write(f.Body)
+	}
+	body.Write(make([]byte, 1024))
+
+	var tag bytes.Buffer
+	tag.WriteString("ID3")
+	tag.Write([]byte{4, 0, 0})
+	tag.Write(syncsafeBytes(body.Len()))
+	tag.Write(body.Bytes())
+	return tag.Bytes()
+}
+
+func replaceID3Frames(frames []id3Frame, replace ...id3Frame) []id3Frame {
+	ids := map[string]bool{}
+	for _, f := range replace {
+		ids[f.ID] = true
+	}
+
+	kept := make([]id3Frame, 0, len(frames)+len(replace))
+	for _, f := range frames {
+		if !ids[f.ID] {
+			kept = append(kept, f)
+		}
+	}
+	return append(kept, replace...)
+}
+
+func numberPair(n, total int) string {
+	switch {
+	case n <= 0:
+		return ""
+	case total > 0:
+		return fmt.Sprintf("%d/%d", n, total)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
+
+func language(lang string) string {
+	lang = strings.ToLower(lang)
+	if len(lang) >= 3 {
+		return lang[:3]
+	}
+	return lang + strings.Repeat(" ", 3-len(lang))
+}
+
+func syncsafe(b []byte) int {
+	return int(b[0]&0x7f)<<21 | int(b[1]&0x7f)<<14 | int(b[2]&0x7f)<<7 | int(b[3]&0x7f)
+}
+
+func syncsafeBytes(n int) []byte {
+	return []byte{byte(n>>21) & 0x7f, byte(n>>14) & 0x7f, byte(n>>7) & 0x7f, byte(n) & 0x7f}
+}
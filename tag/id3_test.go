@@ -0,0 +1,133 @@
+package tag
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+var testAudio = []byte{0xFF, 0xFB, 0x90, 0x00, 'A', 'U', 'D', 'I', 'O'}
+
+// testID3 renders frames as a tag of the given major version, without the
+// padding buildID3 adds.
+func testID3(version byte, frames ...id3Frame) []byte {
+	var body bytes.Buffer
+	for _, f := range frames {
+		body.WriteString(f.ID)
+		if version == 4 {
+			body.Write(syncsafeBytes(len(f.Body)))
+		} else {
+			binary.Write(&body, binary.BigEndian, uint32(len(f.Body)))
+		}
+		body.Write([]byte{0, 0})
+		body.Write(f.Body)
+	}
+
+	var tag bytes.Buffer
+	tag.WriteString("ID3")
+	tag.Write([]byte{version, 0, 0})
+	tag.Write(syncsafeBytes(body.Len()))
+	tag.Write(body.Bytes())
+	return tag.Bytes()
+}
+
+func textFrame(id, value string) id3Frame {
+	return id3Frame{ID: id, Body: append([]byte{3}, value...)}
+}
+
+func frameText(frames []id3Frame, id string) (string, bool) {
+	for _, f := range frames {
+		if f.ID == id {
+			return string(f.Body[1:]), true
+		}
+	}
+	return "", false
+}
+
+func TestTagMP3(t *testing.T) {
+	md := Metadata{Title: "New Title", Artist: "Artist", Date: "2024-05-01", TrackNumber: 2, TrackTotal: 9}
+
+	for _, tc := range []struct {
+		name  string
+		input []byte
+		// want maps frame IDs to their expected text, "" meaning absent.
+		want map[string]string
+	}{
+		{
+			name:  "untagged",
+			input: testAudio,
+			want:  map[string]string{"TIT2": "New Title", "TPE1": "Artist", "TDRC": "2024-05-01", "TRCK": "2/9", "TALB": ""},
+		},
+		{
+			name:  "v2.3 tag",
+			input: append(testID3(3, textFrame("TIT2", "Old Title"), textFrame("TYER", "1999"), textFrame("TALB", "Kept")), testAudio...),
+			want:  map[string]string{"TIT2": "New Title", "TDRC": "2024-05-01", "TYER": "", "TALB": "Kept"},
+		},
+		{
+			name:  "v2.4 tag",
+			input: append(testID3(4, textFrame("TIT2", "Old Title"), textFrame("TCOM", "Composer")), testAudio...),
+			want:  map[string]string{"TIT2": "New Title", "TCOM": "Composer"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tagged, err := tagMP3(tc.input, md)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tagged[3] != 4 {
+				t.Errorf("version = 2.%d, want 2.4", tagged[3])
+			}
+
+			frames, audio, err := splitID3(tagged)
+			if err != nil {
+				t.Fatalf("reading back: %v", err)
+			}
+			if !bytes.Equal(audio, testAudio) {
+				t.Errorf("audio = %x, want %x", audio, testAudio)
+			}
+			for id, want := range tc.want {
+				got, ok := frameText(frames, id)
+				switch {
+				case want == "" && ok:
+					t.Errorf("%s = %q, want no frame", id, got)
+				case want != "" && got != want:
+					t.Errorf("%s = %q, want %q", id, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitID3Errors(t *testing.T) {
+	v22 := testID3(3, textFrame("TIT2", "x"))
+	v22[3] = 2
+	unsync := testID3(4, textFrame("TIT2", "x"))
+	unsync[5] = 0x80
+	truncated := testID3(4, textFrame("TIT2", "title"))
+	truncated = truncated[:len(truncated)-3]
+
+	for name, data := range map[string][]byte{
+		"v2.2":           v22,
+		"unsynchronised": unsync,
+		"truncated":      truncated,
+	} {
+		if _, _, err := splitID3(data); err == nil {
+			t.Errorf("%s: splitID3 succeeded, want an error", name)
+		}
+	}
+}
+
+func TestNumberPair(t *testing.T) {
+	for _, tc := range []struct {
+		n, total int
+		want     string
+	}{
+		{0, 10, ""},
+		{3, 0, "3"},
+		{3, 12, "3/12"},
+	} {
+		if got := numberPair(tc.n, tc.total); got != tc.want {
+			t.Errorf("numberPair(%d, %d) = %q, want %q", tc.n, tc.total, got, tc.want)
+		}
+	}
+}
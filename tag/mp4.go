@@ -0,0 +1,325 @@
+package tag
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+)
+
+type mp4Atom struct {
+	Type string
+	Data []byte
+}
+
+// Atoms whose payload is made only of child atoms, on the way to the chunk
+// offset tables.
+var mp4Containers = map[string]bool{
+	"moov": true, "trak": true, "mdia": true, "minf": true, "stbl": true,
+	"edts": true, "dinf": true, "mvex": true, "moof": true, "traf": true,
+}
+
+func tagM4A(data []byte, md Metadata) ([]byte, error) {
+	top, err := parseAtomPositions(data)
+	if err != nil {
+		return nil, err
+	}
+
+	moovIdx := -1
+	for i, a := range top {
+		if a.Type == "moov" {
+			moovIdx = i
+			break
+		}
+	}
+	if moovIdx < 0 {
+		return nil, errors.New("moov atom not found")
+	}
+	moov := top[moovIdx]
+
+	children, err := parseAtoms(data[moov.Start+moov.Header : moov.End])
+	if err != nil {
+		return nil, err
+	}
+
+	udta := findAtom(children, "udta")
+	udtaChildren, err := parseAtoms(udta.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := findAtom(udtaChildren, "meta")
+	var metaChildren []mp4Atom
+	if len(meta.Data) >= 4 {
+		if metaChildren, err = parseAtoms(meta.Data[4:]); err != nil {
+			return nil, err
+		}
+	}
+	if !hasAtom(metaChildren, "hdlr") {
+		hdlr := make([]byte, 25)
+		copy(hdlr[8:12], "mdir")
+		copy(hdlr[12:16], "appl")
+		metaChildren = append([]mp4Atom{{Type: "hdlr", Data: hdlr}}, metaChildren...)
+	}
+
+	ilst := findAtom(metaChildren, "ilst")
+	items, err := parseAtoms(ilst.Data)
+	if err != nil {
+		return nil, err
+	}
+	items = replaceAtoms(items, ilstItems(md)...)
+
+	ilst.Data = renderAtoms(items)
+	metaChildren = setAtom(metaChildren, ilst)
+	meta.Data = append([]byte{0, 0, 0, 0}, renderAtoms(metaChildren)...)
+	udtaChildren = setAtom(udtaChildren, meta)
+	udta.Data = renderAtoms(udtaChildren)
+	children = setAtom(children, udta)
+
+	newMoov := renderAtoms([]mp4Atom{{Type: "moov", Data: renderAtoms(children)}})
+
+	// Media stored after moov moves by the size difference, so the chunk
+	// offsets that point into it must follow.
+	delta := int64(len(newMoov)) - int64(moov.End-moov.Start)
+	if delta != 0 {
+		if err := shiftChunkOffsets(newMoov[8:], int64(moov.End), delta); err != nil {
+			return nil, err
+		}
+	}
+
+	var out bytes.Buffer
+	out.Grow(len(data) + int(delta))
+	out.Write(data[:moov.Start])
+	out.Write(newMoov)
+	out.Write(data[moov.End:])
+	return out.Bytes(), nil
+}
+
+func ilstItems(md Metadata) []mp4Atom {
+	var items []mp4Atom
+	text := func(typ, value string) {
+		if value != "" {
+			items = append(items, dataAtom(typ, 1, []byte(value)))
+		}
+	}
+
+	text("\xa9nam", md.Title)
+	text("\xa9ART", md.Artist)
+	text("\xa9alb", md.Album)
+	text("aART", md.AlbumArtist)
+	text("\xa9day", md.Date)
+	text("\xa9lyr", md.Lyrics)
+	if md.ISRC != "" {
+		items = append(items, freeformAtom("ISRC", md.ISRC))
+	}
+
+	if md.TrackNumber > 0 {
+		payload := make([]byte, 8)
+		binary.BigEndian.PutUint16(payload[2:], uint16(md.TrackNumber))
+		binary.BigEndian.PutUint16(payload[4:], uint16(md.TrackTotal))
+		items = append(items, dataAtom("trkn", 0, payload))
+	}
+	if md.DiscNumber > 0 {
+		payload := make([]byte, 6)
+		binary.BigEndian.PutUint16(payload[2:], uint16(md.DiscNumber))
+		binary.BigEndian.PutUint16(payload[4:], uint16(md.DiscTotal))
+		items = append(items, dataAtom("disk", 0, payload))
+	}
+
+	if len(md.Cover) > 0 {
+		kind := uint32(13)
+		if md.CoverMIME == "image/png" {
+			kind = 14
+		}
+		items = append(items, dataAtom("covr", kind, md.Cover))
+	}
+
+	return items
+}
+
+func dataAtom(typ string, kind uint32, payload []byte) mp4Atom {
+	data := make([]byte, 8, 8+len(payload))
+	binary.BigEndian.PutUint32(data[0:], kind)
+	data = append(data, payload...)
+	return mp4Atom{Type: typ, Data: renderAtoms([]mp4Atom{{Type: "data", Data: data}})}
+}
+
+// freeformAtom builds an iTunes "----" item, used for fields without a
+// dedicated atom.
+func freeformAtom(name, value string) mp4Atom {
+	mean := append([]byte{0, 0, 0, 0}, "com.apple.iTunes"...)
+	nameData := append([]byte{0, 0, 0, 0}, name...)
+	data := make([]byte, 8, 8+len(value))
+	binary.BigEndian.PutUint32(data[0:], 1)
+	data = append(data, value...)
+
+	return mp4Atom{Type: "----", Data: renderAtoms([]mp4Atom{
+		{Type: "mean", Data: mean},
+		{Type: "name", Data: nameData},
+		{Type: "data", Data: data},
+	})}
+}
+
+func shiftChunkOffsets(payload []byte, after, delta int64) error {
+	for pos := 0; pos+8 <= len(payload); {
+		size := int(binary.BigEndian.Uint32(payload[pos:]))
+		typ := string(payload[pos+4 : pos+8])
+		header := 8
+		if size == 1 {
+			if pos+16 > len(payload) {
+				return errors.New("truncated atom")
+			}
+			size = int(binary.BigEndian.Uint64(payload[pos+8:]))
+			header = 16
+		}
+		if size < header || pos+size > len(payload) {
+			return errors.New("invalid atom size")
+		}
+		body := payload[pos+header : pos+size]
+
+		switch {
+		case mp4Containers[typ]:
+			if err := shiftChunkOffsets(body, after, delta); err != nil {
+				return err
+			}
+		case typ == "stco" && len(body) >= 8:
+			count := int(binary.BigEndian.Uint32(body[4:]))
+			for i := 0; i < count && 8+i*4+4 <= len(body); i++ {
+				off := body[8+i*4:]
+				if v := int64(binary.BigEndian.Uint32(off)); v >= after {
+					binary.BigEndian.PutUint32(off, uint32(v+delta))
+				}
+			}
+		case typ == "co64" && len(body) >= 8:
+			count := int(binary.BigEndian.Uint32(body[4:]))
+			for i := 0; i < count && 8+i*8+8 <= len(body); i++ {
+				off := body[8+i*8:]
+				if v := int64(binary.BigEndian.Uint64(off)); v >= after {
+					binary.BigEndian.PutUint64(off, uint64(v+delta))
+				}
+			}
+		}
+
+		pos += size
+	}
+	return nil
+}
+
+type atomPosition struct {
+	Type   string
+	Start  int
+	Header int
+	End    int
+}
+
+func parseAtomPositions(data []byte) ([]atomPosition, error) {
+	var atoms []atomPosition
+	for pos := 0; pos+8 <= len(data); {
+		size := int(binary.BigEndian.Uint32(data[pos:]))
+		header := 8
+		switch size {
+		case 0:
+			size = len(data) - pos
+		case 1:
+			if pos+16 > len(data) {
+				return nil, errors.New("truncated atom")
+			}
+			size = int(binary.BigEndian.Uint64(data[pos+8:]))
+			header = 16
+		}
+		if size < header || pos+size > len(data) {
+			return nil, errors.New("invalid atom size")
+		}
+		atoms = append(atoms, atomPosition{Type: string(data[pos+4 : pos+8]), Start: pos, Header: header, End: pos + size})
+		pos += size
+	}
+	return atoms, nil
+}
+
+func parseAtoms(data []byte) ([]mp4Atom, error) {
+	positions, err := parseAtomPositions(data)
+	if err != nil {
+		return nil, err
+	}
+	atoms := make([]mp4Atom, 0, len(positions))
+	for _, p := range positions {
+		atoms = append(atoms, mp4Atom{Type: p.Type, Data: data[p.Start+p.Header : p.End]})
+	}
+	return atoms, nil
+}
+
+func renderAtoms(atoms []mp4Atom) []byte {
+	var out bytes.Buffer
+	for _, a := range atoms {
+		if size := 8 + len(a.Data); size <= 0xFFFFFFFF {
+			binary.Write(&out, binary.BigEndian, uint32(size))
+			out.WriteString(a.Type)
+		} else {
+			binary.Write(&out, binary.BigEndian, uint32(1))
+			out.WriteString(a.Type)
+			binary.Write(&out, binary.BigEndian, uint64(size+8))
+		}
+		out.Write(a.Data)
+	}
+	return out.Bytes()
+}
+
+func findAtom(atoms []mp4Atom, typ string) mp4Atom {
+	for _, a := range atoms {
+		if a.Type == typ {
+			return a
+		}
+	}
+	return mp4Atom{Type: typ}
+}
+
+func hasAtom(atoms []mp4Atom, typ string) bool {
+	for _, a := range atoms {
+		if a.Type == typ {
+			return true
+		}
+	}
+	return false
+}
+
+func setAtom(atoms []mp4Atom, atom mp4Atom) []mp4Atom {
+	for i, a := range atoms {
+		if a.Type == atom.Type {
+			atoms[i] = atom
+			return atoms
+		}
+	}
+	return append(atoms, atom)
+}
+
+// replaceAtoms swaps ilst items for the given ones. Freeform items are
+// matched on their name rather than their shared "----" type.
+func replaceAtoms(items []mp4Atom, replace ...mp4Atom) []mp4Atom {
+	keys := map[string]bool{}
+	for _, a := range replace {
+		keys[ilstKey(a)] = true
+	}
+
+	kept := make([]mp4Atom, 0, len(items)+len(replace))
+	for _, a := range items {
+		if !keys[ilstKey(a)] {
+			kept = append(kept, a)
+		}
+	}
+	return append(kept, replace...)
+}
+
+func ilstKey(a mp4Atom) string {
+	if a.Type != "----" {
+		return a.Type
+	}
+	children, err := parseAtoms(a.Data)
+	if err != nil {
+		return a.Type
+	}
+	name := findAtom(children, "name")
+	if len(name.Data) < 4 {
+		return a.Type
+	}
+	return "----:" + strings.ToLower(string(name.Data[4:]))
+}
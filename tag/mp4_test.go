@@ -0,0 +1,140 @@
+package tag
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+var testSamples = []byte("AUDIO-SAMPLES")
+
+// testM4A builds a minimal file whose single track points at the samples
+// in mdat through both an stco and a co64 table. With moovFirst the moov
+// atom precedes mdat, as in files optimised for streaming.
+func testM4A(t *testing.T, moovFirst bool) []byte {
+	t.Helper()
+
+	ftyp := renderAtoms([]mp4Atom{{Type: "ftyp", Data: []byte("M4A \x00\x00\x00\x00M4A ")}})
+	mdat := renderAtoms([]mp4Atom{{Type: "mdat", Data: testSamples}})
+
+	moov := func(offset uint64) []byte {
+		stco := make([]byte, 12)
+		binary.BigEndian.PutUint32(stco[4:], 1)
+		binary.BigEndian.PutUint32(stco[8:], uint32(offset))
+		co64 := make([]byte, 16)
+		binary.BigEndian.PutUint32(co64[4:], 1)
+		binary.BigEndian.PutUint64(co64[8:], offset)
+
+		stbl := renderAtoms([]mp4Atom{{Type: "stco", Data: stco}, {Type: "co64", Data: co64}})
+		minf := renderAtoms([]mp4Atom{{Type: "stbl", Data: stbl}})
+		mdia := renderAtoms([]mp4Atom{{Type: "minf", Data: minf}})
+		trak := renderAtoms([]mp4Atom{{Type: "mdia", Data: mdia}})
+		return renderAtoms([]mp4Atom{{Type: "moov", Data: renderAtoms([]mp4Atom{{Type: "trak", Data: trak}})}})
+	}
+
+	if moovFirst {
+		// The moov size does not depend on the offset it holds.
+		size := len(moov(0))
+		return bytes.Join([][]byte{ftyp, moov(uint64(len(ftyp) + size + 8)), mdat}, nil)
+	}
+	return bytes.Join([][]byte{ftyp, mdat, moov(uint64(len(ftyp) + 8))}, nil)
+}
+
+// chunkOffsets returns the stco and co64 entries of the first track.
+func chunkOffsets(t *testing.T, data []byte) (stco, co64 uint64) {
+	t.Helper()
+
+	var walk func(payload []byte)
+	walk = func(payload []byte) {
+		atoms, err := parseAtoms(payload)
+		if err != nil {
+			t.Fatalf("parse atoms: %v", err)
+		}
+		for _, a := range atoms {
+			switch {
+			case a.Type == "moov" || mp4Containers[a.Type]:
+				walk(a.Data)
+			case a.Type == "stco":
+				stco = uint64(binary.BigEndian.Uint32(a.Data[8:]))
+			case a.Type == "co64":
+				co64 = binary.BigEndian.Uint64(a.Data[8:])
+			}
+		}
+	}
+	walk(data)
+	return stco, co64
+}
+
+func TestTagM4AChunkOffsets(t *testing.T) {
+	md := Metadata{
+		Title:       "Title",
+		Artist:      "Artist",
+		Album:       "Album",
+		ISRC:        "USRC17607839",
+		TrackNumber: 3,
+		TrackTotal:  12,
+		Cover:       bytes.Repeat([]byte{0xFF}, 300),
+		CoverMIME:   "image/jpeg",
+	}
+
+	for _, tc := range []struct {
+		name      string
+		moovFirst bool
+	}{
+		{"moov before mdat", true},
+		{"moov after mdat", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data := testM4A(t, tc.moovFirst)
+			wantStco, _ := chunkOffsets(t, data)
+
+			tagged := data
+			// Tagging twice rewrites an existing ilst, which must keep the
+			// offsets right as well.
+			for round := 1; round <= 2; round++ {
+				var err error
+				if tagged, err = tagM4A(tagged, md); err != nil {
+					t.Fatalf("round %d: %v", round, err)
+				}
+
+				stco, co64 := chunkOffsets(t, tagged)
+				if stco != co64 {
+					t.Fatalf("round %d: stco %d and co64 %d disagree", round, stco, co64)
+				}
+				if end := stco + uint64(len(testSamples)); end > uint64(len(tagged)) {
+					t.Fatalf("round %d: offset %d points past the end of the file", round, stco)
+				}
+				if got := tagged[stco : stco+uint64(len(testSamples))]; !bytes.Equal(got, testSamples) {
+					t.Fatalf("round %d: offset %d points at %q, want the samples", round, stco, got)
+				}
+				if !tc.moovFirst && stco != wantStco {
+					t.Fatalf("round %d: offset before moov moved from %d to %d", round, wantStco, stco)
+				}
+			}
+
+			if !bytes.Contains(tagged, []byte("USRC17607839")) {
+				t.Error("ISRC was not written")
+			}
+		})
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"id3", []byte("ID3\x04\x00"), "mp3"},
+		{"mpeg1 layer 3", []byte{0xFF, 0xFB, 0x90, 0x00}, "mp3"},
+		{"mpeg2 layer 3", []byte{0xFF, 0xF3, 0x90, 0x00}, "mp3"},
+		{"adts aac", []byte{0xFF, 0xF1, 0x50, 0x80}, ""},
+		{"adts aac mpeg2", []byte{0xFF, 0xF9, 0x50, 0x80}, ""},
+		{"m4a", []byte("\x00\x00\x00\x20ftypM4A "), "m4a"},
+		{"empty", nil, ""},
+	} {
+		if got := DetectFormat(tc.data); got != tc.want {
+			t.Errorf("%s: DetectFormat = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package tag
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Beesonn/dlkitgo/spotify"
+)
+
+type SyncedLine struct {
+	Time time.Duration `json:"time"`
+	Text string        `json:"text"`
+}
+
+// Metadata holds the tags to write. Empty fields leave the existing tag in
+// the file untouched.
+type Metadata struct {
+	Title        string       `json:"title"`
+	Artist       string       `json:"artist"`
+	Album        string       `json:"album"`
+	AlbumArtist  string       `json:"album_artist"`
+	Date         string       `json:"date"`
+	TrackNumber  int          `json:"track_number"`
+	TrackTotal   int          `json:"track_total"`
+	DiscNumber   int          `json:"disc_number"`
+	DiscTotal    int          `json:"disc_total"`
+	ISRC         string       `json:"isrc"`
+	Lyrics       string       `json:"lyrics"`
+	SyncedLyrics []SyncedLine `json:"synced_lyrics,omitempty"`
+	Language     string       `json:"language"`
+	CoverURL     string       `json:"cover_url"`
+	Cover        []byte       `json:"-"`
+	CoverMIME    string       `json:"cover_mime"`
+}
+
+type TagService struct {
+	Client *http.Client
+}
+
+func NewTag(client *http.Client) *TagService {
+	return &TagService{Client: client}
+}
+
+func FromTrackSource(src spotify.TrackSource) Metadata {
+	return Metadata{
+		Title:       src.Title,
+		Artist:      src.Artist,
+		Album:       src.Album,
+		AlbumArtist: src.AlbumArtist,
+		Date:        src.ReleaseDate,
+		TrackNumber: src.TrackNumber,
+		DiscNumber:  src.DiscNumber,
+		ISRC:        src.ISRC,
+		CoverURL:    src.Image,
+	}
+}
+
+// TagFile tags a file downloaded from a TrackSource URL, fetching its cover.
+func (t *TagService) TagFile(path string, src spotify.TrackSource, lyrics string) error {
+	md := FromTrackSource(src)
+	md.Lyrics = lyrics
+	return t.Write(path, md)
+}
+
+// Write is the package level Write, but first downloads CoverURL if no
+// cover image is set. A failed download does not prevent tagging.
+func (t *TagService) Write(path string, md Metadata) error {
+	if len(md.Cover) == 0 && md.CoverURL != "" {
+		if cover, mime, err := t.FetchCover(md.CoverURL); err == nil {
+			md.Cover, md.CoverMIME = cover, mime
+		}
+	}
+	return Write(path, md)
+}
+
+func (t *TagService) FetchCover(coverURL string) ([]byte, string, error) {
+	client := t.Client
+	if client == nil {
+		client = &http.Client{}
+	}
+
+	resp, err := client.Get(coverURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch cover: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("cover returned status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, "", err
+	}
+
+	mime := http.DetectContentType(data)
+	if mime != "image/jpeg" && mime != "image/png" {
+		return nil, "", fmt.Errorf("unsupported cover type: %s", mime)
+	}
+	return data, mime, nil
+}
+
+// Write tags an MP3 or M4A file in place, detecting the format from its
+// content rather than its extension.
+func Write(path string, md Metadata) error {
+	if len(md.Cover) > 0 && md.CoverMIME == "" {
+		md.CoverMIME = http.DetectContentType(md.Cover)
+	}
+	if md.Language == "" {
+		md.Language = "eng"
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var tagged []byte
+	switch DetectFormat(data) {
+	case "mp3":
+		tagged, err = tagMP3(data, md)
+	case "m4a":
+		tagged, err = tagM4A(data, md)
+	default:
+		return errors.New("unsupported audio format")
+	}
+	if err != nil {
+		return err
+	}
+
+	return replaceFile(path, tagged)
+}
+
+func DetectFormat(data []byte) string {
+	switch {
+	case len(data) >= 3 && string(data[:3]) == "ID3":
+		return "mp3"
+	// An MPEG audio frame sync with non-zero layer bits. ADTS AAC shares
+	// the sync word but always has layer 00, and must not get ID3 frames.
+	case len(data) >= 2 && data[0] == 0xFF && data[1]&0xE0 == 0xE0 && data[1]&0x06 != 0:
+		return "mp3"
+	case len(data) >= 8 && string(data[4:8]) == "ftyp":
+		return "m4a"
+	}
+	return ""
+}
+
+func replaceFile(path string, data []byte) error {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+strings.TrimPrefix(filepath.Base(path), ".")+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, bytes.NewReader(data)); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), stat.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 )
 
+// TestSpInfo prints the metadata of a fixed Spotify track as JSON.
+//
+// Deprecated: use "dlkit info" instead.
 func TestSpInfo() {
 	client := NewClient()
 	url := "https://open.spotify.com/track/0B6ZJaS3I891FP8Ewx43Oh"
@@ -13,9 +13,12 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/Beesonn/dlkitgo/errkind"
 )
 
 type YouTubeVideoInfo struct {
+	ID       string `json:"id"`
 	Name     string `json:"name"`
 	URL      string `json:"url"`
 	Duration int    `json:"duration"`
@@ -48,7 +51,7 @@ func (t *TubeService) GetInfo(url string) (YouTubeData, error) {
 
 	contentType, id := detectYouTubeType(url)
 	if contentType == "" {
-		return YouTubeData{}, errors.New("unsupported YouTube URL (only video or shorts)")
+		return YouTubeData{}, errkind.New(errkind.ErrInvalidURL, "unsupported YouTube URL (only video or shorts)")
 	}
 
 	if strings.Contains(url, "&si=") {
@@ -116,6 +119,7 @@ func (t *TubeService) parseVideoPageDirect(url string, result *YouTubeData) erro
 	}
 
 	videoInfo := YouTubeVideoInfo{
+		ID:       result.ID,
 		Name:     result.Name,
 		URL:      result.URL,
 		Duration: duration,
@@ -138,6 +142,7 @@ func (t *TubeService) getInfoFromAPI(originalURL, contentType, id string, result
 	result.Image = apiData.Thumbnail
 
 	videoInfo := YouTubeVideoInfo{
+		ID:       result.ID,
 		Name:     apiData.Title,
 		URL:      originalURL,
 		Duration: apiData.Duration,
@@ -230,9 +235,18 @@ func decryptAESCBC(encryptedData string) ([]byte, error) {
 	return ciphertext[:len(ciphertext)-paddingLen], nil
 }
 
+// VideoID returns the 11 character ID of a video or shorts URL.
+func VideoID(url string) (string, error) {
+	_, id := detectYouTubeType(url)
+	if id == "" {
+		return "", errkind.New(errkind.ErrInvalidURL, "unsupported YouTube URL (only video or shorts)")
+	}
+	return id, nil
+}
+
 func detectYouTubeType(url string) (typ string, id string) {
 	videoPatterns := []*regexp.Regexp{
-		regexp.MustCompile(`(?:youtube\.com/watch\?v=|youtu\.be/)([a-zA-Z0-9_-]{11})`),
+		regexp.MustCompile(`(?:youtube\.com/watch\?(?:[^#]*&)?v=|youtu\.be/)([a-zA-Z0-9_-]{11})`),
 		regexp.MustCompile(`youtube\.com/shorts/([a-zA-Z0-9_-]{11})`),
 	}
 	for _, re := range videoPatterns {
@@ -0,0 +1,23 @@
+package youtube
+
+import "testing"
+
+func TestVideoID(t *testing.T) {
+	for _, tc := range []struct {
+		url, want string
+	}{
+		{"https://www.youtube.com/watch?v=dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://www.youtube.com/watch?feature=share&v=dQw4w9WgXcQ&t=42", "dQw4w9WgXcQ"},
+		{"https://m.youtube.com/watch?v=dQw4w9WgXcQ&si=abc", "dQw4w9WgXcQ"},
+		{"https://youtu.be/dQw4w9WgXcQ?si=abc", "dQw4w9WgXcQ"},
+		{"https://www.youtube.com/shorts/aBcD_eF-123", "aBcD_eF-123"},
+		{"https://www.youtube.com/watch?list=PL123#v=dQw4w9WgXcQ", ""},
+		{"https://www.youtube.com/playlist?list=PL123", ""},
+		{"https://example.com/watch?v=dQw4w9WgXcQ", ""},
+	} {
+		got, err := VideoID(tc.url)
+		if got != tc.want || (err == nil) != (tc.want != "") {
+			t.Errorf("VideoID(%q) = %q, %v, want %q", tc.url, got, err, tc.want)
+		}
+	}
+}
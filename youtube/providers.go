@@ -2,7 +2,10 @@ package youtube
 
 import (
 	"net/http"
+	"regexp"
+	"strings"
 
+	"github.com/Beesonn/dlkitgo/capability"
 	"github.com/Beesonn/dlkitgo/youtube/providers"
 )
 
@@ -12,9 +15,32 @@ type Provider interface {
 	Stream(url string) (providers.YTResults, error)
 }
 
+var ChannelURLRegex = regexp.MustCompile(`youtube\.com/(?:c/|channel/|user/|@)`)
+
 func DefaultProviders(client *http.Client) []Provider {
 	return []Provider{
 		&providers.SaveTube{Client: client},
 		&providers.VidVaults{Client: client},
 	}
 }
+
+// ContentKind returns the capability kind of a YouTube link, or an empty
+// string if it is not one.
+func ContentKind(url string) string {
+	switch {
+	case !providers.IsYouTubeURL(url) && !ChannelURLRegex.MatchString(url):
+		return ""
+	case strings.Contains(url, "/shorts/"):
+		return capability.Short
+	case strings.Contains(url, "/live/"):
+		return capability.Live
+	case ChannelURLRegex.MatchString(url):
+		return capability.Channel
+	}
+	return capability.Video
+}
+
+// ProvidersFor returns the configured providers that support kind.
+func (t *TubeService) ProvidersFor(kind string) []Provider {
+	return capability.Filter(t.Providers, kind)
+}
@@ -10,6 +10,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+
+	"github.com/Beesonn/dlkitgo/capability"
+	"github.com/Beesonn/dlkitgo/errkind"
 )
 
 type SaveTube struct {
@@ -48,13 +51,20 @@ func (p *SaveTube) BaseURL() string {
 	return "https://media.savetube.vip"
 }
 
+func (p *SaveTube) Capabilities() capability.Capabilities {
+	return capability.Capabilities{
+		Kinds:      []string{capability.Video, capability.Short, capability.Live},
+		MaxQuality: "1080p",
+	}
+}
+
 func (p *SaveTube) Stream(url string) (YTResults, error) {
 	if url == "" {
 		return YTResults{}, errors.New("url cannot be empty")
 	}
 
 	if !IsYouTubeURL(url) {
-		return YTResults{}, errors.New("invalid YouTube URL")
+		return YTResults{}, errkind.New(errkind.ErrInvalidURL, "invalid YouTube URL")
 	}
 
 	info, err := p.getVideoInfo(url)
@@ -10,6 +10,7 @@ type YTSource struct {
 }
 
 type YTResults struct {
+	ID        string     `json:"id,omitempty"`
 	Caption   string     `json:"caption"`
 	Thumbnail string     `json:"thumbnail"`
 	Duration  int        `json:"duration"`
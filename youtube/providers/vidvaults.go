@@ -7,6 +7,9 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+
+	"github.com/Beesonn/dlkitgo/capability"
+	"github.com/Beesonn/dlkitgo/errkind"
 )
 
 type VidVaults struct {
@@ -21,13 +24,20 @@ func (p *VidVaults) BaseURL() string {
 	return "https://api.vidvaults.com"
 }
 
+func (p *VidVaults) Capabilities() capability.Capabilities {
+	return capability.Capabilities{
+		Kinds:      []string{capability.Video, capability.Short, capability.Live},
+		MaxQuality: "4K",
+	}
+}
+
 func (p *VidVaults) Stream(url string) (YTResults, error) {
 	if url == "" {
 		return YTResults{}, errors.New("url cannot be empty")
 	}
 
 	if !IsYouTubeURL(url) {
-		return YTResults{}, errors.New("invalid YouTube URL")
+		return YTResults{}, errkind.New(errkind.ErrInvalidURL, "invalid YouTube URL")
 	}
 
 	apiResponse, err := p.DoRequest(url)
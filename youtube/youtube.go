@@ -4,7 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
 
+	"github.com/Beesonn/dlkitgo/cookies"
+	"github.com/Beesonn/dlkitgo/errkind"
 	"github.com/Beesonn/dlkitgo/youtube/providers"
 )
 
@@ -20,21 +23,36 @@ func NewTube(client *http.Client) *TubeService {
 	}
 }
 
+// UseCookies reads YouTube pages with the cookies in jar. Only GetInfo and
+// Search use them; the download providers are third-party sites that
+// fetch YouTube themselves, so age-gated videos still fail to stream.
+func (t *TubeService) UseCookies(jar http.CookieJar) {
+	t.Client = cookies.Client(t.Client, jar)
+}
+
 func (t *TubeService) Stream(url string) (providers.YTResults, error) {
 	if url == "" {
 		return providers.YTResults{}, errors.New("url cannot be empty")
 	}
 
 	if !providers.IsYouTubeURL(url) {
-		return providers.YTResults{}, fmt.Errorf("invalid YouTube URL")
+		return providers.YTResults{}, errkind.New(errkind.ErrInvalidURL, "invalid YouTube URL")
 	}
 
-	for _, provider := range t.Providers {
+	kind := ContentKind(url)
+	list := t.ProvidersFor(kind)
+	if len(list) == 0 {
+		return providers.YTResults{}, errkind.New(errkind.ErrProvidersFailed, "no configured provider supports %s links", kind)
+	}
+	for _, provider := range list {
 		res, err := provider.Stream(url)
 		if err == nil {
+			if res.ID == "" {
+				res.ID, _ = VideoID(url)
+			}
 			return res, nil
 		}
-		fmt.Printf("Provider '%s' failed to stream: %v\n", provider.Name(), err)
+		fmt.Fprintf(os.Stderr, "Provider '%s' failed to stream: %v\n", provider.Name(), err)
 	}
-	return providers.YTResults{}, errors.New("all configured providers failed to stream the content")
+	return providers.YTResults{}, errkind.New(errkind.ErrProvidersFailed, "all configured providers failed to stream the content")
 }
@@ -5,6 +5,9 @@ import (
 	"fmt"
 )
 
+// YtTest streams a fixed YouTube video and prints the result as JSON.
+//
+// Deprecated: use "dlkit stream" instead.
 func YtTest() {
 	client := NewClient()
 	url := "https://youtu.be/YVkUvmDQ3HY?si=WX_soUJPp66u-mcF"